@@ -0,0 +1,59 @@
+package parsort
+
+import (
+	"errors"
+)
+
+// errCanceled is the panic value stopping the goroutines of a sort whose context is done.
+var errCanceled = errors.New("parsort: sort canceled")
+
+// canceled reports whether the recovered panic value r stopped a canceled sort.
+func canceled(r any) bool {
+	if pe, ok := r.(*PanicError); ok {
+		r = pe.Value
+	}
+	return r == errCanceled
+}
+
+// Handle is a sort running in the background, started by one of the Async functions.
+//
+// The slice being sorted must not be read or written until Done is closed or Wait returns,
+// including after the context of the Sorter is canceled: the sort only stops at its next phase.
+// Once Done is closed the slice is sorted when Err returns nil. After an error other than the
+// memory budget's, its contents are unspecified.
+type Handle struct {
+	done chan struct{}
+	err  error
+}
+
+// async runs sort on a new goroutine and returns its Handle.
+func async(sort func() error) *Handle {
+	h := &Handle{done: make(chan struct{})}
+	go func() {
+		defer close(h.done)
+		h.err = sort()
+	}()
+	return h
+}
+
+// Wait blocks until the sort finished and returns its error.
+func (x *Handle) Wait() error {
+	<-x.done
+	return x.err
+}
+
+// Done returns a channel closed when the sort finished.
+func (x *Handle) Done() <-chan struct{} {
+	return x.done
+}
+
+// Err returns the error of the sort once Done is closed, nil while it is running.
+// A panic during the sort is returned as a *PanicError, cancellation as the context's error.
+func (x *Handle) Err() error {
+	select {
+	case <-x.done:
+		return x.err
+	default:
+		return nil
+	}
+}
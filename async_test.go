@@ -0,0 +1,100 @@
+package parsort
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+)
+
+func TestIntAscAsync(t *testing.T) {
+	withCoreCount(t, 4)
+	data := genInts(100000)
+	h := IntAscAsync(data)
+	if err := h.Wait(); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	select {
+	case <-h.Done():
+	default:
+		t.Errorf("Done not closed after Wait")
+	}
+	if h.Err() != nil || !sort.IntsAreSorted(data) {
+		t.Errorf("IntAscAsync failed")
+	}
+
+	desc := genStrings(20000)
+	if err := NewSorter().StringDescAsync(desc).Wait(); err != nil || !sort.IsSorted(sort.Reverse(sort.StringSlice(desc))) {
+		t.Errorf("StringDescAsync failed: %v", err)
+	}
+}
+
+func TestAsync_Running(t *testing.T) {
+	withCoreCount(t, 4)
+	release := make(chan struct{})
+	var once sync.Once
+	data := genPeople(50000)
+	h := StructAscAsync(data, func(a, b person) bool {
+		once.Do(func() { <-release })
+		return a.Age < b.Age
+	})
+	if h.Err() != nil {
+		t.Errorf("Err must be nil while running")
+	}
+	select {
+	case <-h.Done():
+		t.Errorf("Done closed while the sort is blocked")
+	default:
+	}
+	close(release)
+	<-h.Done()
+	if h.Err() != nil || !isSortedAsc(data) {
+		t.Errorf("StructAscAsync failed: %v", h.Err())
+	}
+}
+
+func TestAsync_CanceledBeforeStart(t *testing.T) {
+	withCoreCount(t, 4)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	data := genInts(100000)
+	orig := append([]int(nil), data...)
+	err := NewSorter().WithContext(ctx).IntAscAsync(data).Wait()
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if !intSlicesEqual(data, orig) {
+		t.Errorf("data modified by a canceled sort")
+	}
+}
+
+func TestAsync_CanceledWhileSorting(t *testing.T) {
+	withCoreCount(t, 4)
+	for _, st := range []Strategy{PairwiseMerge, KWayMerge, Samplesort, InPlace} {
+		ctx, cancel := context.WithCancel(context.Background())
+		s := NewSorter().SetStrategy(st).WithContext(ctx)
+		h := StructAscAsyncWith(s, genPeople(100000), func(a, b person) bool {
+			cancel()
+			return a.Age < b.Age
+		})
+		if err := h.Wait(); !errors.Is(err, context.Canceled) {
+			t.Errorf("%s: expected context.Canceled, got %v", st, err)
+		}
+		cancel()
+	}
+}
+
+func TestAsync_Panic(t *testing.T) {
+	withCoreCount(t, 4)
+	h := StructAscStableAsync(genPeople(50000), func(a, b person) bool {
+		if a.Age == 50 {
+			panic("boom")
+		}
+		return a.Age < b.Age
+	})
+	var pe *PanicError
+	if err := h.Wait(); !errors.As(err, &pe) || pe.Value != "boom" {
+		t.Errorf("expected a *PanicError, got %v", err)
+	}
+}
@@ -0,0 +1,315 @@
+package parsort
+
+import (
+	"math/bits"
+	"sort"
+	"sync/atomic"
+	"unsafe"
+)
+
+const (
+	// autoWindows is the number of evenly spaced windows Auto inspects for sortedness.
+	autoWindows = 64
+	// autoWindowSize is the number of consecutive elements in every window.
+	autoWindowSize = 16
+	// autoSampleSize is the number of evenly spaced elements Auto inspects for duplicates and keys.
+	autoSampleSize = 1024
+	// autoLargeElem is the element size in bytes from which moving elements dominates comparing them.
+	autoLargeElem = 64
+	// autoHugeElem is the element size in bytes from which a full size merge buffer is avoided.
+	autoHugeElem = 512
+	// autoLongPrefix is the mean shared string prefix length from which comparisons are considered expensive.
+	autoLongPrefix = 16
+)
+
+// Decision describes how a sort call was executed and, for Auto, what the sample looked like.
+type Decision struct {
+	// Strategy is the strategy that ran, Sequential when the slice was sorted on the calling goroutine.
+	Strategy Strategy
+	// N is the length of the sorted slice.
+	N int
+	// Threshold is the MinParallelSize value of the element type.
+	Threshold int
+	// Cores is the number of cores the sort could use.
+	Cores int
+	// Chunks is the number of chunks the input was split into, 1 for Sequential. Radix and
+	// Samplesort split it into blocks, one per core.
+	Chunks int
+	// ExtraBytes is the estimated upper bound on the bytes allocated by the sort, see EstimateExtraBytes.
+	ExtraBytes int64
+	// Sampled is set when Auto inspected the input. The fields below are only filled in that case.
+	Sampled bool
+	// Sortedness is the fraction of sampled neighbouring pairs already in ascending order.
+	// When the sampled pairs are all in order, every pair of the input is inspected instead.
+	Sortedness float64
+	// DuplicateRatio is the fraction of sampled elements equal to another sampled element.
+	DuplicateRatio float64
+	// KeyBits is the number of low key bits that vary across the sample, 0 when the type has no radix key.
+	KeyBits int
+	// PrefixShare is the mean common prefix length of neighbouring sampled strings, 0 for other types.
+	PrefixShare float64
+	// ElemSize is the size of one element in bytes, of an index when Indirect is set.
+	ElemSize int
+	// Indirect is set when the struct elements were sorted through an array of their indexes,
+	// see StructIndirectSize. ExtraBytes then includes the index array.
+	Indirect bool
+	// Reason is a short human readable explanation of the choice.
+	Reason string
+}
+
+// decisionLog keeps the most recent Decision of a Sorter. It is replaced with an atomic
+// store, skipped when the decision didn't change, so that concurrent sorts don't wait on each
+// other and repeated ones don't allocate. A nil log records nothing.
+type decisionLog struct {
+	last atomic.Value // *Decision
+}
+
+func (x *decisionLog) record(d Decision) {
+	if x == nil {
+		return
+	}
+	if last, _ := x.last.Load().(*Decision); last != nil && *last == d {
+		return
+	}
+	p := new(Decision)
+	*p = d
+	x.last.Store(p)
+}
+
+func (x *decisionLog) get() Decision {
+	if x == nil {
+		return Decision{}
+	}
+	last, _ := x.last.Load().(*Decision)
+	if last == nil {
+		return Decision{}
+	}
+	return *last
+}
+
+// packageDecisions records the decisions of the package level functions.
+var packageDecisions decisionLog
+
+// LastDecision returns the decision of the most recent call to a package level sort function.
+// With concurrent callers it is the decision of whichever call finished deciding last.
+func LastDecision() Decision {
+	return packageDecisions.get()
+}
+
+// decide returns how to sort data and records the decision on s.
+// It returns an error when nothing fits in the memory budget of s.
+func decide[T any](s *Sorter, data []T, threshold int, ops *typeOps[T]) (Decision, error) {
+	d := Decision{
+		N:         len(data),
+		Threshold: threshold,
+		Cores:     cores(),
+	}
+	budget := s.maxExtraBytes
+	if ops.indirect {
+		d.Indirect = true
+		if budget > 0 {
+			budget -= indexBytes(d.N)
+		}
+	}
+	if choose(&d, s.strategy, ops.key != nil) {
+		autoDecide(&d, data, ops, budget)
+	}
+	if d.Sampled && budget > 0 {
+		budget -= autoSampleBytes
+	}
+	err := fitBudget(&d, shapeOf(ops), budget)
+	if ops.indirect {
+		d.ExtraBytes += indexBytes(d.N)
+	}
+	if d.Sampled {
+		d.ExtraBytes += autoSampleBytes
+	}
+	s.decisions.record(d)
+	return d, err
+}
+
+// choose fills in the strategy for st before the memory budget is applied. It reports
+// whether the caller has to make the choice, when st is Auto and the slice can be sorted
+// in parallel.
+func choose(d *Decision, st Strategy, key bool) (auto bool) {
+	switch {
+	case d.Cores <= 1 && st != Sequential:
+		d.Strategy = Sequential
+		d.Reason = "single core"
+	case st == Auto:
+		return true
+	case st == Sequential || d.N < d.Threshold || d.N < 2:
+		d.Strategy = Sequential
+		d.Reason = "below threshold"
+		if st == Sequential {
+			d.Reason = "sequential strategy"
+		}
+	default:
+		d.Strategy = resolveStrategy(st, key)
+		d.Reason = "configured strategy"
+	}
+	return false
+}
+
+// autoDecide samples data and fills in the strategy Auto will use. budget is the memory
+// budget left for the sort, sampling takes autoSampleBytes of it.
+func autoDecide[T any](d *Decision, data []T, ops *typeOps[T], budget int64) {
+	d.ElemSize = int(unsafe.Sizeof(*new(T)))
+	if !autoSkip(d, budget) {
+		d.Sampled = true
+		autoSample(d, data, ops)
+		autoChoose(d, ops.key != nil, ops.stable)
+	}
+}
+
+// autoSkip chooses Sequential without sampling when nothing can pay off below half the
+// threshold, or when the sample doesn't fit in the budget. It reports whether it did.
+func autoSkip(d *Decision, budget int64) bool {
+	switch {
+	case d.N < d.Threshold/2 || d.N < 2:
+		d.Strategy = Sequential
+		d.Reason = "below threshold"
+	case budget > 0 && budget <= autoSampleBytes:
+		d.Strategy = Sequential
+		d.Reason = "memory budget"
+	default:
+		return false
+	}
+	return true
+}
+
+// autoChoose picks the strategy for the sample described by d. key tells whether the type has
+// a radix key, stable whether the sort must be stable.
+func autoChoose(d *Decision, key, stable bool) {
+	threshold := d.Threshold
+	if d.PrefixShare >= autoLongPrefix || d.ElemSize >= autoLargeElem {
+		threshold /= 2
+	}
+
+	switch {
+	case d.N < threshold:
+		d.Strategy = Sequential
+		d.Reason = "below threshold"
+	case d.Sortedness == 1:
+		d.Strategy = Sequential
+		d.Reason = "input is already in ascending order, there is nothing to merge"
+	case d.Sortedness == 0 && !stable && descendingLinear:
+		// Stable sorts and the introsort before Go 1.19 don't detect descending runs.
+		d.Strategy = Sequential
+		d.Reason = "input is in descending order, the sequential sort reverses it in linear time"
+	case key && 2*((d.KeyBits+7)/8) < bits.Len(uint(d.N)):
+		d.Strategy = Radix
+		d.Reason = "few radix passes compared to log2(n)"
+	case d.ElemSize >= autoHugeElem:
+		d.Strategy = InPlace
+		d.Reason = "elements too large for a full size merge buffer"
+	case d.ElemSize >= autoLargeElem && d.DuplicateRatio < 0.5:
+		d.Strategy = Samplesort
+		d.Reason = "large elements, samplesort moves each element twice"
+	default:
+		d.Strategy = PairwiseMerge
+		d.Reason = "general case"
+	}
+}
+
+// autoSample fills in the sampled properties of d.
+func autoSample[T any](d *Decision, data []T, ops *typeOps[T]) {
+	n := len(data)
+
+	// Sortedness from consecutive windows spread over the input.
+	windows := autoWindows
+	size := autoWindowSize
+	if windows*size > n {
+		windows = 1
+		size = n
+	}
+	step := n / windows
+	asc, desc, pairs := 0, 0, 0
+	for w := 0; w < windows; w++ {
+		start := w * step
+		a, de := countOrderedPairs(data[start:start+size], ops.less)
+		asc += a
+		desc += de
+		pairs += size - 1
+	}
+	// Windows in order can't tell sorted input from concatenated sorted runs, confirm it
+	// on every pair before Auto sorts sequentially on the strength of the sample.
+	if (asc == pairs || desc == pairs) && pairs < n-1 {
+		asc, desc = countOrderedPairs(data, ops.less)
+		pairs = n - 1
+	}
+	switch {
+	case pairs == 0:
+		d.Sortedness = 1
+	case desc == pairs && asc != pairs:
+		d.Sortedness = 0
+	default:
+		d.Sortedness = float64(asc) / float64(pairs)
+	}
+
+	// Duplicates, key width and prefix sharing from an evenly spaced sorted sample.
+	m := autoSampleSize
+	if m > n {
+		m = n
+	}
+	// The sample holds indexes, so that its size doesn't depend on the element size.
+	sample := make([]int, m)
+	for i := range sample {
+		sample[i] = (i * n) / m
+	}
+	sort.Slice(sample, func(i, j int) bool {
+		return ops.less(data[sample[i]], data[sample[j]])
+	})
+
+	dups := 0
+	prefix := 0
+	for i := 1; i < m; i++ {
+		a, b := data[sample[i-1]], data[sample[i]]
+		if !ops.less(a, b) {
+			dups++
+		}
+		if ops.prefix != nil {
+			prefix += ops.prefix(a, b)
+		}
+	}
+	if m > 1 {
+		d.DuplicateRatio = float64(dups) / float64(m-1)
+		d.PrefixShare = float64(prefix) / float64(m-1)
+	}
+
+	if ops.key != nil {
+		first := ops.key(data[sample[0]])
+		var diff uint64
+		for _, i := range sample[1:] {
+			diff |= ops.key(data[i]) ^ first
+		}
+		d.KeyBits = bits.Len64(diff)
+	}
+}
+
+// countOrderedPairs returns how many neighbouring pairs of data are in ascending and in
+// descending order. Pairs of equal elements count as both.
+func countOrderedPairs[T any](data []T, less func(a, b T) bool) (asc, desc int) {
+	for i := 1; i < len(data); i++ {
+		if !less(data[i], data[i-1]) {
+			asc++
+		}
+		if !less(data[i-1], data[i]) {
+			desc++
+		}
+	}
+	return asc, desc
+}
+
+// commonPrefix returns the length of the common prefix of a and b.
+func commonPrefix(a, b string) int {
+	if len(b) < len(a) {
+		a, b = b, a
+	}
+	for i := 0; i < len(a); i++ {
+		if a[i] != b[i] {
+			return i
+		}
+	}
+	return len(a)
+}
@@ -0,0 +1,236 @@
+package parsort
+
+import (
+	"sort"
+	"sync"
+	"testing"
+)
+
+func TestAuto_BelowThreshold(t *testing.T) {
+	s := NewSorter().SetStrategy(Auto)
+	data := genInts(100)
+	s.IntAsc(data)
+	d := s.LastDecision()
+	if d.Strategy != Sequential || d.Sampled {
+		t.Errorf("expected unsampled Sequential decision, got %+v", d)
+	}
+	if !sort.IntsAreSorted(data) {
+		t.Errorf("slice not sorted")
+	}
+}
+
+func TestAuto_AlreadySorted(t *testing.T) {
+	withCoreCount(t, 4)
+	s := NewSorter().SetStrategy(Auto)
+	data := make([]int, 100000)
+	for i := range data {
+		data[i] = i
+	}
+	s.IntAsc(data)
+	if d := s.LastDecision(); d.Strategy != Sequential || !d.Sampled || d.Sortedness != 1 {
+		t.Errorf("expected sampled Sequential decision, got %+v", d)
+	}
+
+	for i := range data {
+		data[i] = len(data) - i
+	}
+	s.IntAsc(data)
+	if d := s.LastDecision(); d.Sortedness != 0 || (d.Strategy == Sequential) != descendingLinear {
+		t.Errorf("unexpected decision for reversed input, got %+v", d)
+	}
+	if !sort.IntsAreSorted(data) {
+		t.Errorf("slice not sorted")
+	}
+}
+
+func TestAuto_ReversedStable(t *testing.T) {
+	withCoreCount(t, 4)
+	s := NewSorter().SetStrategy(Auto)
+	// sort.SliceStable doesn't detect descending runs, so reversed input is sorted in parallel.
+	data := make([]person, 100000)
+	for i := range data {
+		data[i] = person{Name: "p", Age: len(data) - i}
+	}
+	less := func(a, b person) bool { return a.Age < b.Age }
+	if err := StructAscStableWith(s, data, less); err != nil {
+		t.Fatal(err)
+	}
+	if d := s.LastDecision(); d.Strategy == Sequential || d.Sortedness != 0 {
+		t.Errorf("expected a parallel decision for reversed stable input, got %+v", d)
+	}
+	if !sort.SliceIsSorted(data, func(i, j int) bool { return data[i].Age < data[j].Age }) {
+		t.Errorf("slice not sorted")
+	}
+}
+
+func TestAuto_ConcatenatedRuns(t *testing.T) {
+	withCoreCount(t, 4)
+	s := NewSorter().SetStrategy(Auto)
+	// Every sampled window falls inside one of the two sorted runs.
+	data := make([]int, 100000)
+	for i := range data {
+		data[i] = i % (len(data) / 2)
+	}
+	s.IntAsc(data)
+	if d := s.LastDecision(); d.Strategy == Sequential || d.Sortedness == 1 {
+		t.Errorf("expected a parallel decision for two sorted runs, got %+v", d)
+	}
+	if !sort.IntsAreSorted(data) {
+		t.Errorf("slice not sorted")
+	}
+}
+
+func TestAuto_RandomIntsUseRadix(t *testing.T) {
+	withCoreCount(t, 4)
+	s := NewSorter().SetStrategy(Auto)
+	data := genInts(300000)
+	expected := append([]int(nil), data...)
+	sort.Ints(expected)
+	s.IntAsc(data)
+	d := s.LastDecision()
+	if d.Strategy != Radix || d.KeyBits == 0 {
+		t.Errorf("expected Radix decision, got %+v", d)
+	}
+	if !intSlicesEqual(data, expected) {
+		t.Errorf("sorted result incorrect")
+	}
+}
+
+func TestAuto_StringsUseMerge(t *testing.T) {
+	withCoreCount(t, 4)
+	s := NewSorter().SetStrategy(Auto)
+	data := genStrings(50000)
+	expected := append([]string(nil), data...)
+	sort.Strings(expected)
+	s.StringAsc(data)
+	d := s.LastDecision()
+	if d.Strategy != PairwiseMerge || d.PrefixShare < 3 || d.KeyBits != 0 {
+		t.Errorf("expected PairwiseMerge with shared prefixes, got %+v", d)
+	}
+	if !stringSlicesEqual(data, expected) {
+		t.Errorf("sorted result incorrect")
+	}
+}
+
+func TestAuto_LargeStructs(t *testing.T) {
+	type large struct {
+		Key     int
+		Payload [15]int
+	}
+	type huge struct {
+		Key     int
+		Payload [127]int
+	}
+	withCoreCount(t, 4)
+	s := NewSorter().SetStrategy(Auto).SetIndirectSize(0)
+
+	l := make([]large, 20000)
+	for i := range l {
+		l[i].Key = genInts(1)[0]
+	}
+	StructAscWith(s, l, func(a, b large) bool { return a.Key < b.Key })
+	if d := s.LastDecision(); d.Strategy != Samplesort || d.ElemSize != 128 {
+		t.Errorf("expected Samplesort for large elements, got %+v", d)
+	}
+	if !sort.SliceIsSorted(l, func(i, j int) bool { return l[i].Key < l[j].Key }) {
+		t.Errorf("large structs not sorted")
+	}
+
+	h := make([]huge, 20000)
+	for i := range h {
+		h[i].Key = genInts(1)[0]
+	}
+	StructAscWith(s, h, func(a, b huge) bool { return a.Key < b.Key })
+	if d := s.LastDecision(); d.Strategy != InPlace {
+		t.Errorf("expected InPlace for huge elements, got %+v", d)
+	}
+	if !sort.SliceIsSorted(h, func(i, j int) bool { return h[i].Key < h[j].Key }) {
+		t.Errorf("huge structs not sorted")
+	}
+}
+
+func TestAuto_PackageDefault(t *testing.T) {
+	withCoreCount(t, 4)
+	prev := DefaultStrategy
+	DefaultStrategy = Auto
+	defer func() { DefaultStrategy = prev }()
+
+	data := genFloats(100000)
+	expected := append([]float64(nil), data...)
+	sort.Float64s(expected)
+	Float64Asc(data)
+	if d := LastDecision(); !d.Sampled || d.N != 100000 || d.Threshold != Float64MinParallelSize {
+		t.Errorf("unexpected package decision %+v", d)
+	}
+	if !floatSlicesEqual(data, expected) {
+		t.Errorf("sorted result incorrect")
+	}
+}
+
+func TestAuto_SmallSortsDontAllocate(t *testing.T) {
+	withCoreCount(t, 4)
+	src := genInts(64)
+	data := make([]int, len(src))
+	s := NewSorter().SetStrategy(Auto)
+	allocs := testing.AllocsPerRun(100, func() {
+		copy(data, src)
+		IntAsc(data)
+	})
+	if allocs != 0 {
+		t.Errorf("expected no allocations from IntAsc, got %v per sort", allocs)
+	}
+	allocs = testing.AllocsPerRun(100, func() {
+		copy(data, src)
+		_ = s.IntAsc(data)
+	})
+	if allocs != 0 {
+		t.Errorf("expected no allocations from an Auto Sorter, got %v per sort", allocs)
+	}
+}
+
+func TestAuto_ZeroSorter(t *testing.T) {
+	withCoreCount(t, 4)
+	var s Sorter
+	data := genInts(100000)
+	if err := s.IntAsc(data); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if !sort.IntsAreSorted(data) {
+		t.Errorf("slice not sorted")
+	}
+	if d := s.LastDecision(); d != (Decision{}) {
+		t.Errorf("expected no decision, got %+v", d)
+	}
+}
+
+func TestAuto_ConcurrentDecisions(t *testing.T) {
+	withCoreCount(t, 4)
+	s := NewSorter()
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			for i := 0; i < 20; i++ {
+				_ = s.IntAsc(genInts(n))
+				IntAsc(genInts(n))
+			}
+		}(1000 * (g + 1))
+	}
+	wg.Wait()
+	if d := s.LastDecision(); d.N%1000 != 0 || d.N == 0 {
+		t.Errorf("unexpected last decision %+v", d)
+	}
+}
+
+func TestSequentialStrategy(t *testing.T) {
+	s := NewSorter().SetStrategy(Sequential)
+	data := genInts(100000)
+	s.IntDesc(data)
+	if d := s.LastDecision(); d.Strategy != Sequential || d.Reason != "sequential strategy" {
+		t.Errorf("unexpected decision %+v", d)
+	}
+	if !sort.IsSorted(sort.Reverse(sort.IntSlice(data))) {
+		t.Errorf("slice not sorted in descending order")
+	}
+}
@@ -0,0 +1,88 @@
+package parsort
+
+import (
+	"os"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// cgroupRoot is where the cgroup filesystems are mounted.
+const cgroupRoot = "/sys/fs/cgroup"
+
+// cgroupCPUQuota returns the CPU quota of the process's cgroup in whole cores, 0 when there is none.
+func cgroupCPUQuota() int {
+	return cgroupQuota(os.ReadFile)
+}
+
+// cgroupQuota reads the cgroup v1 or v2 CPU quota through readFile.
+// Both the cgroup's own directory and the mount root are tried, since inside a container
+// the cgroup namespace usually makes the process's cgroup the root.
+func cgroupQuota(readFile func(name string) ([]byte, error)) int {
+	self, err := readFile("/proc/self/cgroup")
+	if err != nil {
+		return 0
+	}
+
+	for _, line := range strings.Split(strings.TrimSpace(string(self)), "\n") {
+		// hierarchy-ID:controller-list:cgroup-path
+		fields := strings.SplitN(line, ":", 3)
+		if len(fields) != 3 {
+			continue
+		}
+
+		if fields[0] == "0" && fields[1] == "" {
+			for _, dir := range []string{path.Join(cgroupRoot, fields[2]), cgroupRoot} {
+				if b, err := readFile(dir + "/cpu.max"); err == nil {
+					return parseCPUMax(string(b))
+				}
+			}
+			continue
+		}
+
+		for _, controller := range strings.Split(fields[1], ",") {
+			if controller != "cpu" {
+				continue
+			}
+			for _, mount := range []string{cgroupRoot + "/cpu,cpuacct", cgroupRoot + "/cpu"} {
+				for _, dir := range []string{path.Join(mount, fields[2]), mount} {
+					quota, err := readInt(readFile, dir+"/cpu.cfs_quota_us")
+					if err != nil {
+						continue
+					}
+					period, err := readInt(readFile, dir+"/cpu.cfs_period_us")
+					if err != nil {
+						continue
+					}
+					return quotaCores(quota, period)
+				}
+			}
+		}
+	}
+	return 0
+}
+
+// parseCPUMax parses a cgroup v2 cpu.max file, "$MAX $PERIOD" where $MAX may be "max".
+func parseCPUMax(s string) int {
+	fields := strings.Fields(s)
+	if len(fields) != 2 || fields[0] == "max" {
+		return 0
+	}
+	quota, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return 0
+	}
+	period, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil {
+		return 0
+	}
+	return quotaCores(quota, period)
+}
+
+func readInt(readFile func(name string) ([]byte, error), name string) (int64, error) {
+	b, err := readFile(name)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(strings.TrimSpace(string(b)), 10, 64)
+}
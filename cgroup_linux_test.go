@@ -0,0 +1,61 @@
+package parsort
+
+import (
+	"os"
+	"testing"
+)
+
+func fakeFiles(files map[string]string) func(name string) ([]byte, error) {
+	return func(name string) ([]byte, error) {
+		if s, ok := files[name]; ok {
+			return []byte(s), nil
+		}
+		return nil, os.ErrNotExist
+	}
+}
+
+func TestCgroupQuota_V2(t *testing.T) {
+	files := map[string]string{
+		"/proc/self/cgroup":                       "0::/kubepods/pod1/c1\n",
+		"/sys/fs/cgroup/kubepods/pod1/c1/cpu.max": "400000 100000\n",
+	}
+	if got := cgroupQuota(fakeFiles(files)); got != 4 {
+		t.Errorf("expected 4 cores, got %d", got)
+	}
+
+	// Inside a cgroup namespace the cgroup is mounted as the root.
+	files = map[string]string{
+		"/proc/self/cgroup":      "0::/\n",
+		"/sys/fs/cgroup/cpu.max": "150000 100000\n",
+	}
+	if got := cgroupQuota(fakeFiles(files)); got != 2 {
+		t.Errorf("expected a 1.5 core quota to round up to 2, got %d", got)
+	}
+
+	files["/sys/fs/cgroup/cpu.max"] = "max 100000\n"
+	if got := cgroupQuota(fakeFiles(files)); got != 0 {
+		t.Errorf("expected no quota, got %d", got)
+	}
+}
+
+func TestCgroupQuota_V1(t *testing.T) {
+	files := map[string]string{
+		"/proc/self/cgroup":                            "12:memory:/docker/abc\n4:cpu,cpuacct:/docker/abc\n0::/\n",
+		"/sys/fs/cgroup/cpu,cpuacct/cpu.cfs_quota_us":  "300000\n",
+		"/sys/fs/cgroup/cpu,cpuacct/cpu.cfs_period_us": "100000\n",
+	}
+	if got := cgroupQuota(fakeFiles(files)); got != 3 {
+		t.Errorf("expected 3 cores, got %d", got)
+	}
+
+	files["/sys/fs/cgroup/cpu,cpuacct/cpu.cfs_quota_us"] = "-1\n"
+	if got := cgroupQuota(fakeFiles(files)); got != 0 {
+		t.Errorf("expected no quota, got %d", got)
+	}
+}
+
+func TestCgroupQuota_Missing(t *testing.T) {
+	if got := cgroupQuota(fakeFiles(nil)); got != 0 {
+		t.Errorf("expected no quota without /proc/self/cgroup, got %d", got)
+	}
+}
@@ -0,0 +1,8 @@
+//go:build !linux
+
+package parsort
+
+// cgroupCPUQuota returns 0, cgroups only exist on Linux.
+func cgroupCPUQuota() int {
+	return 0
+}
@@ -0,0 +1,116 @@
+package parsort
+
+import (
+	"fmt"
+	"os"
+	"sync/atomic"
+)
+
+// checkSampleSize is the number of elements whose pairs and triples are checked by CheckLess.
+const checkSampleSize = 32
+
+// checkRound shifts the sampled positions between calls, so repeated sorts of the same slice
+// check different elements.
+var checkRound uint32
+
+// ComparatorError reports a less function that isn't a strict weak ordering, found by CheckLess.
+type ComparatorError struct {
+	// Rule is the violated property: "irreflexivity", "asymmetry", "transitivity"
+	// or "transitivity of equivalence".
+	Rule string
+	// Index holds the positions in the input of the offending pair or triple.
+	Index []int
+	// Values holds the offending elements, in the order of Index.
+	Values []any
+}
+
+func (x *ComparatorError) Error() string {
+	v, i := x.Values, x.Index
+	switch x.Rule {
+	case "irreflexivity":
+		return fmt.Sprintf("parsort: less violates %s: less(x, x) is true for x = data[%d] = %+v", x.Rule, i[0], v[0])
+	case "asymmetry":
+		return fmt.Sprintf("parsort: less violates %s: less(a, b) and less(b, a) are both true for a = data[%d] = %+v, b = data[%d] = %+v",
+			x.Rule, i[0], v[0], i[1], v[1])
+	case "transitivity":
+		return fmt.Sprintf("parsort: less violates %s: less(a, b) and less(b, c) but not less(a, c) for a = data[%d] = %+v, b = data[%d] = %+v, c = data[%d] = %+v",
+			x.Rule, i[0], v[0], i[1], v[1], i[2], v[2])
+	}
+	return fmt.Sprintf("parsort: less violates %s: a ~ b and b ~ c but not a ~ c for a = data[%d] = %+v, b = data[%d] = %+v, c = data[%d] = %+v",
+		x.Rule, i[0], v[0], i[1], v[1], i[2], v[2])
+}
+
+// checkLessFromEnv reports whether the PARSORT_CHECK_LESS environment variable enables CheckLess.
+func checkLessFromEnv() bool {
+	switch os.Getenv("PARSORT_CHECK_LESS") {
+	case "", "0", "false":
+		return false
+	}
+	return true
+}
+
+// checkStrictWeakOrder checks less against the rules of a strict weak ordering on every pair and
+// triple of up to checkSampleSize evenly spaced elements of data. It returns the first violation.
+func checkStrictWeakOrder[T any](data []T, less func(a, b T) bool) *ComparatorError {
+	n := len(data)
+	if n == 0 {
+		return nil
+	}
+	m := n
+	if m > checkSampleSize {
+		m = checkSampleSize
+	}
+	stride := n / m
+	offset := 0
+	if stride > 1 {
+		offset = int(atomic.AddUint32(&checkRound, 1)) % stride
+	}
+	idx := make([]int, m)
+	for k := range idx {
+		idx[k] = offset + k*stride
+	}
+
+	// lt[a*m+b] caches less(data[idx[a]], data[idx[b]]).
+	lt := make([]bool, m*m)
+	for a := 0; a < m; a++ {
+		for b := 0; b < m; b++ {
+			lt[a*m+b] = less(data[idx[a]], data[idx[b]])
+		}
+	}
+
+	report := func(rule string, ks ...int) *ComparatorError {
+		e := &ComparatorError{Rule: rule}
+		for _, k := range ks {
+			e.Index = append(e.Index, idx[k])
+			e.Values = append(e.Values, data[idx[k]])
+		}
+		return e
+	}
+	eq := func(a, b int) bool {
+		return !lt[a*m+b] && !lt[b*m+a]
+	}
+
+	for a := 0; a < m; a++ {
+		if lt[a*m+a] {
+			return report("irreflexivity", a)
+		}
+		for b := a + 1; b < m; b++ {
+			if lt[a*m+b] && lt[b*m+a] {
+				return report("asymmetry", a, b)
+			}
+		}
+	}
+	for a := 0; a < m; a++ {
+		for b := 0; b < m; b++ {
+			for c := 0; c < m; c++ {
+				if lt[a*m+b] && lt[b*m+c] && !lt[a*m+c] {
+					return report("transitivity", a, b, c)
+				}
+				if eq(a, b) && eq(b, c) && !eq(a, c) {
+					return report("transitivity of equivalence", a, b, c)
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package parsort
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCheckLess_Consistent(t *testing.T) {
+	s := NewSorter().SetCheckLess(true)
+	data := genPeople(50000)
+	if err := StructAscStableWith(s, data, func(a, b person) bool { return a.Age < b.Age }); err != nil {
+		t.Fatalf("unexpected error for a consistent less: %v", err)
+	}
+	if !isSortedAsc(data) {
+		t.Errorf("slice not sorted")
+	}
+}
+
+func TestCheckLess_Violations(t *testing.T) {
+	s := NewSorter().SetCheckLess(true)
+	cases := []struct {
+		name string
+		less func(a, b person) bool
+		rule string
+	}{
+		{"less or equal", func(a, b person) bool { return a.Age <= b.Age }, "irreflexivity"},
+		{"not equal", func(a, b person) bool { return a.Age != b.Age }, "asymmetry"},
+		{"rock paper scissors", func(a, b person) bool { return (a.Age+1)%3 == b.Age%3 }, "transitivity"},
+		{"close enough", func(a, b person) bool { return a.Age+10 < b.Age }, "transitivity of equivalence"},
+	}
+	for _, c := range cases {
+		data := genPeople(1000)
+		original := append([]person(nil), data...)
+		err := StructAscWith(s, data, c.less)
+
+		var ce *ComparatorError
+		if !errors.As(err, &ce) {
+			t.Errorf("%s: expected a *ComparatorError, got %v", c.name, err)
+			continue
+		}
+		if ce.Rule != c.rule {
+			t.Errorf("%s: expected rule %q, got %q", c.name, c.rule, ce.Rule)
+		}
+		for k, i := range ce.Index {
+			if ce.Values[k].(person) != original[i] {
+				t.Errorf("%s: value %v doesn't match data[%d]", c.name, ce.Values[k], i)
+			}
+		}
+		if !strings.Contains(err.Error(), c.rule) {
+			t.Errorf("%s: expected the report to name the rule, got %q", c.name, err)
+		}
+		for i := range data {
+			if data[i] != original[i] {
+				t.Fatalf("%s: data modified at %d despite the error", c.name, i)
+			}
+		}
+	}
+}
+
+func TestCheckLess_PanicsInPackageFunctions(t *testing.T) {
+	prev := CheckLess
+	CheckLess = true
+	defer func() { CheckLess = prev }()
+
+	defer func() {
+		if _, ok := recover().(*ComparatorError); !ok {
+			t.Errorf("expected a *ComparatorError panic")
+		}
+	}()
+	StructAsc(genPeople(100), func(a, b person) bool { return a.Age <= b.Age })
+}
+
+func TestCheckLess_FromEnv(t *testing.T) {
+	t.Setenv("PARSORT_CHECK_LESS", "1")
+	if !checkLessFromEnv() {
+		t.Errorf("expected PARSORT_CHECK_LESS=1 to enable the check")
+	}
+	t.Setenv("PARSORT_CHECK_LESS", "0")
+	if checkLessFromEnv() {
+		t.Errorf("expected PARSORT_CHECK_LESS=0 to disable the check")
+	}
+}
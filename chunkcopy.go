@@ -0,0 +1,27 @@
+package parsort
+
+// chunkCopyMergeSort copies every chunk into a buffer, sorts the copies in parallel and then
+// merges them pairwise between the buffer and data. data is only written with sorted runs.
+// The tree is oriented so that the deepest chunks are sorted in the buffer, chunks one level
+// higher are copied back into data once sorted.
+func chunkCopyMergeSort[T any](data []T, ops *typeOps[T]) {
+	chunks := ops.split(len(data))
+	buffer := ops.buffer(len(data))
+
+	root, other := data, buffer
+	if mergeTreeDepth(len(chunks))%2 == 0 {
+		root, other = buffer, data
+	}
+	pingPongTree(root, other, chunks, ops, func(i int, dst []T) {
+		c := buffer[chunks[i].start:chunks[i].end]
+		copy(c, data[chunks[i].start:chunks[i].end])
+		ops.sort(c)
+		if &dst[0] == &data[0] {
+			copy(data[chunks[i].start:chunks[i].end], c)
+		}
+	})
+
+	if &root[0] != &data[0] {
+		parallelCopy(data, buffer)
+	}
+}
@@ -1,39 +1,67 @@
 package parsort
 
-import (
-	"runtime"
-)
-
 var (
 	// CoreCount determines the number of parallel operations to perform.
-	// By default, it uses all available CPU cores.
-	CoreCount = runtime.NumCPU()
+	// When it is 0, the default, it follows runtime.GOMAXPROCS capped by the CPU quota of the
+	// process's cgroup on Linux, so containers with a CPU limit don't use the host's core count.
+	// With a single core available, every sort runs sequentially.
+	CoreCount = 0
+
+	// MaxWorkers limits the number of goroutines parsort runs next to the calling goroutines.
+	// The limit is shared by all concurrent and nested sorts of the process: a sort that finds
+	// it exhausted uses fewer cores, down to sorting on the calling goroutine alone.
+	// Zero or a negative value means the number of cores sorts use, see CoreCount.
+	MaxWorkers = 0
+
+	// TasksPerCore is the number of chunks created per core for the chunk sorting phase.
+	// Cores that finish their chunks early take over chunks queued for slower cores, so one
+	// slow chunk no longer holds back the whole sort. A value of 1 disables over-decomposition.
+	TasksPerCore = 4
+
+	// MinChunkSize is the minimum number of elements per chunk. Slices above their
+	// MinParallelSize threshold but too small to give every core a chunk of this size
+	// are split into fewer chunks and use fewer cores.
+	MinChunkSize = 2048
+
+	// CheckLess makes the struct functions check, before sorting, that less is a strict weak
+	// ordering on a sample of the input. Violations are returned by the Sorter functions as a
+	// *ComparatorError and panic in the package functions. It is meant for debugging and is
+	// enabled by setting the PARSORT_CHECK_LESS environment variable, disabled it costs nothing.
+	CheckLess = checkLessFromEnv()
+
+	// Verify makes every sort check its output: that it is in order, in parallel, and that it is a
+	// permutation of the input, using an order independent hash of the elements taken before sorting.
+	// Failures are passed to VerifyHook as a *VerifyError, the sort itself doesn't fail.
+	// It is meant for canaries and is enabled by setting the PARSORT_VERIFY environment variable.
+	Verify = verifyFromEnv()
+
+	// VerifyHook receives the failures found by Verify. When nil, they are logged with the log package.
+	VerifyHook func(err *VerifyError)
+
+	// DefaultObserver receives the events of the package level functions, and is the initial
+	// Observer of every Sorter created by NewSorter. Nil disables the events.
+	DefaultObserver Observer
+
+	// StructIndirectSize is the element size in bytes, as given by unsafe.Sizeof, from which the
+	// struct functions sort indirectly: an array of indexes is sorted in parallel and the resulting
+	// permutation is applied once at the end, so every element is moved once instead of once per
+	// merge level. Only slices sorted in parallel are sorted indirectly.
+	// It is the initial value of every Sorter created by NewSorter, 0 disables it.
+	StructIndirectSize = 256
+
+	// DefaultStrategy is the algorithm used by the package level functions, and the initial
+	// strategy of every Sorter created by NewSorter.
+	DefaultStrategy = PairwiseMerge
 
 	// MinParallelSize variables define the threshold at which parallel sorting becomes
 	// more efficient than sequential sorting for each data type.
 	// For slices smaller than these values, sequential sorting is used instead.
 	// These values can be fine-tuned for specific hardware using the Tune() function.
-	
-	// Integer type thresholds
-	IntMinParallelSize   = 10000
-	Int8MinParallelSize  = 5000
-	Int16MinParallelSize = 5000
-	Int32MinParallelSize = 5000
-	Int64MinParallelSize = 5000
-
-	// Unsigned integer type thresholds
-	UintMinParallelSize   = 5000
-	Uint8MinParallelSize  = 5000
-	Uint16MinParallelSize = 5000
-	Uint32MinParallelSize = 5000
-	Uint64MinParallelSize = 5000
-
-	// Floating point type thresholds
-	Float32MinParallelSize = 5000
-	Float64MinParallelSize = 10000
 
-	// Other type thresholds
-	StringMinParallelSize = 10000
-	StructMinParallelSize = 10000
-	TimeMinParallelSize   = 5000
+	// Thresholds that don't depend on the Go version. The others live in
+	// config_go121.go and config_go118.go since their sequential sort differs.
+	IntMinParallelSize     = 10000
+	Float64MinParallelSize = 10000
+	StringMinParallelSize  = 10000
+	StructMinParallelSize  = 10000
 )
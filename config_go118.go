@@ -0,0 +1,25 @@
+//go:build !go1.21
+
+package parsort
+
+// Before Go 1.21 these types are sorted with sort.Slice.
+var (
+	// Integer type thresholds
+	Int8MinParallelSize  = 5000
+	Int16MinParallelSize = 5000
+	Int32MinParallelSize = 5000
+	Int64MinParallelSize = 5000
+
+	// Unsigned integer type thresholds
+	UintMinParallelSize   = 5000
+	Uint8MinParallelSize  = 5000
+	Uint16MinParallelSize = 5000
+	Uint32MinParallelSize = 5000
+	Uint64MinParallelSize = 5000
+
+	// Floating point type thresholds
+	Float32MinParallelSize = 5000
+
+	// Other type thresholds
+	TimeMinParallelSize = 5000
+)
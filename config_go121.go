@@ -0,0 +1,26 @@
+//go:build go1.21
+
+package parsort
+
+// From Go 1.21 these types are sorted with slices.Sort (pdqsort without a reflection based swapper),
+// which roughly halves the sequential baseline, so parallel sorting pays off later than on older versions.
+var (
+	// Integer type thresholds
+	Int8MinParallelSize  = 10000
+	Int16MinParallelSize = 10000
+	Int32MinParallelSize = 10000
+	Int64MinParallelSize = 10000
+
+	// Unsigned integer type thresholds
+	UintMinParallelSize   = 10000
+	Uint8MinParallelSize  = 10000
+	Uint16MinParallelSize = 10000
+	Uint32MinParallelSize = 10000
+	Uint64MinParallelSize = 10000
+
+	// Floating point type thresholds
+	Float32MinParallelSize = 10000
+
+	// Other type thresholds
+	TimeMinParallelSize = 10000
+)
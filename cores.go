@@ -0,0 +1,42 @@
+package parsort
+
+import (
+	"runtime"
+	"sync"
+)
+
+var (
+	cgroupOnce  sync.Once
+	cgroupCores int
+)
+
+// cores returns the number of cores a sort may use: CoreCount when it is set, the detected
+// parallelism of the process otherwise.
+func cores() int {
+	if CoreCount > 0 {
+		return CoreCount
+	}
+	return detectedCores()
+}
+
+// detectedCores returns GOMAXPROCS capped by the CPU quota of the process's cgroup.
+// GOMAXPROCS is read on every call, so runtime.GOMAXPROCS changes apply to the next sort.
+// The quota is only read once.
+func detectedCores() int {
+	n := runtime.GOMAXPROCS(0)
+	cgroupOnce.Do(func() {
+		cgroupCores = cgroupCPUQuota()
+	})
+	if cgroupCores > 0 && cgroupCores < n {
+		n = cgroupCores
+	}
+	return n
+}
+
+// quotaCores converts a CFS quota and period to whole cores, rounding up. It returns 0 when there is no quota.
+func quotaCores(quota, period int64) int {
+	if quota <= 0 || period <= 0 {
+		return 0
+	}
+	return int((quota + period - 1) / period)
+}
@@ -0,0 +1,57 @@
+package parsort
+
+import (
+	"runtime"
+	"sort"
+	"testing"
+)
+
+func TestCores_FollowsGOMAXPROCS(t *testing.T) {
+	withCoreCount(t, 0)
+	prev := runtime.GOMAXPROCS(2)
+	defer runtime.GOMAXPROCS(prev)
+
+	if got := cores(); got < 1 || got > 2 {
+		t.Errorf("expected at most 2 cores with GOMAXPROCS=2, got %d", got)
+	}
+
+	runtime.GOMAXPROCS(1)
+	if got := cores(); got != 1 {
+		t.Errorf("expected 1 core after lowering GOMAXPROCS, got %d", got)
+	}
+
+	s := NewSorter().SetStrategy(KWayMerge)
+	data := genInts(100000)
+	s.IntAsc(data)
+	if d := s.LastDecision(); d.Strategy != Sequential || d.Cores != 1 {
+		t.Errorf("expected a sequential sort on a single core, got %+v", d)
+	}
+	if !sort.IntsAreSorted(data) {
+		t.Errorf("slice not sorted")
+	}
+}
+
+func TestCores_CoreCountOverrides(t *testing.T) {
+	withCoreCount(t, 3)
+	if got := cores(); got != 3 {
+		t.Errorf("expected CoreCount to take precedence, got %d", got)
+	}
+}
+
+func TestQuotaCores(t *testing.T) {
+	cases := []struct {
+		quota, period int64
+		want          int
+	}{
+		{400000, 100000, 4},
+		{50000, 100000, 1},
+		{250000, 100000, 3},
+		{-1, 100000, 0},
+		{100000, 0, 0},
+	}
+	for _, c := range cases {
+		if got := quotaCores(c.quota, c.period); got != c.want {
+			t.Errorf("quotaCores(%d, %d) = %d, expected %d", c.quota, c.period, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,7 @@
+//go:build !go1.19
+
+package parsort
+
+// descendingLinear tells whether the unstable sequential sorts reverse descending input in
+// linear time. Before Go 1.19 sort.Slice is an introsort, which doesn't detect descending runs.
+const descendingLinear = false
@@ -0,0 +1,7 @@
+//go:build go1.19
+
+package parsort
+
+// descendingLinear tells whether the unstable sequential sorts reverse descending input in
+// linear time. From Go 1.19 sort.Slice and slices.Sort are pdqsorts, which detect descending runs.
+const descendingLinear = true
@@ -1,33 +1,22 @@
+// Package experimental holds the sorts that became strategies of parsort.
+//
+// Deprecated: set the strategy on a parsort.Sorter instead, see parsort.Strategy.
 package experimental
 
 import (
-	"runtime"
+	"github.com/rah-0/parsort"
 )
 
 var (
-	CoreCount = runtime.NumCPU()
+	// Deprecated: the sorts use the cores given by parsort.CoreCount. CoreCount has no effect.
+	CoreCount = 0
 )
 
-func mergeIntoBuffer[T any](dst, a, b []T, less func(a, b T) bool) {
-	i, j, k := 0, 0, 0
-	for i < len(a) && j < len(b) {
-		if less(a[i], b[j]) {
-			dst[k] = a[i]
-			i++
-		} else {
-			dst[k] = b[j]
-			j++
-		}
-		k++
-	}
-	for i < len(a) {
-		dst[k] = a[i]
-		i++
-		k++
-	}
-	for j < len(b) {
-		dst[k] = b[j]
-		j++
-		k++
+// sortWith sorts data by less with strategy, raising the errors of the Sorter methods as
+// panics as the package level functions of parsort do.
+func sortWith[T any](strategy parsort.Strategy, data []T, less func(a, b T) bool) {
+	s := parsort.NewSorter().SetStrategy(strategy)
+	if err := parsort.StructAscWith(s, data, less); err != nil {
+		panic(err)
 	}
 }
@@ -0,0 +1,48 @@
+package parsort
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+// errCompareFailed is the panic value stopping the goroutines of a sort whose comparison failed.
+var errCompareFailed = errors.New("parsort: comparison failed")
+
+// fallible turns a comparison that can fail into a less function. The first error is kept and
+// every later comparison, in any goroutine, panics so that all workers stop promptly.
+type fallible[T any] struct {
+	cmp    func(a, b T) (int, error)
+	failed int32
+	once   sync.Once
+	err    error
+}
+
+func (x *fallible[T]) less(a, b T) bool {
+	if atomic.LoadInt32(&x.failed) != 0 {
+		panic(errCompareFailed)
+	}
+	c, err := x.cmp(a, b)
+	if err != nil {
+		x.once.Do(func() { x.err = err })
+		atomic.StoreInt32(&x.failed, 1)
+		panic(errCompareFailed)
+	}
+	return c < 0
+}
+
+// structSortErr sorts data with cmp into a copy and copies the result back once every comparison
+// succeeded, so that data is left untouched when one fails.
+func structSortErr[T any](data []T, cmp func(a, b T) (int, error), s *Sorter) error {
+	f := &fallible[T]{cmp: cmp}
+	sorted := make([]T, len(data))
+	err := sortSliceInto(sorted, data, s, StructMinParallelSize, structOps(f.less, false))
+	if atomic.LoadInt32(&f.failed) != 0 {
+		return f.err
+	}
+	if err != nil {
+		return err
+	}
+	parallelCopy(data, sorted)
+	return nil
+}
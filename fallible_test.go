@@ -0,0 +1,101 @@
+package parsort
+
+import (
+	"errors"
+	"strconv"
+	"sync/atomic"
+	"testing"
+)
+
+type priced struct {
+	ID    int
+	Price string
+}
+
+func genPriced(n int) []priced {
+	data := make([]priced, n)
+	for i, v := range genInts(n) {
+		data[i] = priced{ID: i, Price: strconv.Itoa(v % 100000)}
+	}
+	return data
+}
+
+func comparePrices(a, b priced) (int, error) {
+	x, err := strconv.Atoi(a.Price)
+	if err != nil {
+		return 0, err
+	}
+	y, err := strconv.Atoi(b.Price)
+	if err != nil {
+		return 0, err
+	}
+	return x - y, nil
+}
+
+func TestStructAscErr(t *testing.T) {
+	withCoreCount(t, 4)
+	data := genPriced(50000)
+	if err := StructAscErr(data, comparePrices); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	for i := 1; i < len(data); i++ {
+		if c, _ := comparePrices(data[i-1], data[i]); c > 0 {
+			t.Fatalf("not sorted at %d", i)
+		}
+	}
+}
+
+func TestStructAscErr_Failure(t *testing.T) {
+	withCoreCount(t, 4)
+	for _, st := range strategies {
+		data := genPriced(50000)
+		data[31337].Price = "12.5x"
+		orig := append([]priced(nil), data...)
+
+		var failed, late int32
+		err := StructAscErrWith(NewSorter().SetStrategy(st), data, func(a, b priced) (int, error) {
+			if atomic.LoadInt32(&failed) != 0 {
+				atomic.AddInt32(&late, 1)
+			}
+			c, err := comparePrices(a, b)
+			if err != nil {
+				atomic.StoreInt32(&failed, 1)
+			}
+			return c, err
+		})
+		var numErr *strconv.NumError
+		if !errors.As(err, &numErr) {
+			t.Errorf("%s: expected the comparison error, got %v", st, err)
+		}
+		for i := range data {
+			if data[i] != orig[i] {
+				t.Fatalf("%s: data modified at %d", st, i)
+			}
+		}
+		// Only comparisons already running when the first one failed may still complete.
+		if late > 8 {
+			t.Errorf("%s: %d comparisons ran after the failure", st, late)
+		}
+	}
+}
+
+func TestStructAscErr_Panic(t *testing.T) {
+	withCoreCount(t, 4)
+	data := genPriced(50000)
+	orig := append([]priced(nil), data...)
+	err := StructAscErr(data, func(a, b priced) (int, error) {
+		if a.ID == 777 {
+			panic("boom")
+		}
+		return comparePrices(a, b)
+	})
+	var pe *PanicError
+	if !errors.As(err, &pe) || pe.Value != "boom" {
+		t.Errorf("expected a *PanicError, got %v", err)
+	}
+	for i := range data {
+		if data[i] != orig[i] {
+			t.Fatalf("data modified at %d", i)
+		}
+	}
+}
@@ -1,102 +1,102 @@
 package parsort
 
-import (
-	"sort"
-	"sync"
-)
-
 func Float32Asc(data []float32) {
-	float32Sort(data, false)
+	float32Sort(data, false, defaultSorter())
 }
 
 func Float32Desc(data []float32) {
-	float32Sort(data, true)
-}
-
-func float32Sort(data []float32, reverse bool) {
-	n := len(data)
-	if n < Float32MinParallelSize {
-		sort.Slice(data, func(i, j int) bool {
-			return data[i] < data[j]
-		})
-		if reverse {
-			float32Reverse(data)
-		}
-		return
-	}
+	float32Sort(data, true, defaultSorter())
+}
 
-	chunkSize := (n + CoreCount - 1) / CoreCount
+// Float32Asc sorts data in ascending order using the Sorter's configuration.
+// It returns an error when the Sorter's memory budget can't be met, leaving data untouched,
+// or a *PanicError when sorting panics.
+func (x *Sorter) Float32Asc(data []float32) error {
+	return float32Sort(data, false, x)
+}
 
-	chunks := make([][]float32, 0, CoreCount)
-	for i := 0; i < n; i += chunkSize {
-		end := i + chunkSize
-		if end > n {
-			end = n
-		}
-		chunks = append(chunks, data[i:end])
-	}
+// Float32Desc sorts data in descending order using the Sorter's configuration.
+// It returns an error when the Sorter's memory budget can't be met, leaving data untouched,
+// or a *PanicError when sorting panics.
+func (x *Sorter) Float32Desc(data []float32) error {
+	return float32Sort(data, true, x)
+}
 
-	var wg sync.WaitGroup
-	for _, chunk := range chunks {
-		wg.Add(1)
-		go func(c []float32) {
-			defer wg.Done()
-			sort.Slice(c, func(i, j int) bool {
-				return c[i] < c[j]
-			})
-		}(chunk)
-	}
-	wg.Wait()
-
-	for len(chunks) > 1 {
-		mergedCount := (len(chunks) + 1) / 2
-		merged := make([][]float32, mergedCount)
-
-		var mWg sync.WaitGroup
-		for i := 0; i < len(chunks); i += 2 {
-			if i+1 == len(chunks) {
-				merged[i/2] = chunks[i]
-				continue
-			}
-			mWg.Add(1)
-			go func(i int) {
-				defer mWg.Done()
-				merged[i/2] = float32MergeSorted(chunks[i], chunks[i+1])
-			}(i)
-		}
-		mWg.Wait()
-		chunks = merged
-	}
+// Float32AscAsync starts sorting data in ascending order in the background, see Handle for when data may be used.
+func Float32AscAsync(data []float32) *Handle {
+	return async(func() error { return float32Sort(data, false, returningSorter()) })
+}
+
+// Float32DescAsync starts sorting data in descending order in the background, see Handle for when data may be used.
+func Float32DescAsync(data []float32) *Handle {
+	return async(func() error { return float32Sort(data, true, returningSorter()) })
+}
+
+// Float32AscAsync is Float32AscAsync using the Sorter's configuration, cancelled with the context of WithContext.
+func (x *Sorter) Float32AscAsync(data []float32) *Handle {
+	return async(func() error { return float32Sort(data, false, x) })
+}
+
+// Float32DescAsync is Float32DescAsync using the Sorter's configuration, cancelled with the context of WithContext.
+func (x *Sorter) Float32DescAsync(data []float32) *Handle {
+	return async(func() error { return float32Sort(data, true, x) })
+}
+
+// Float32Sorted returns a copy of src sorted in ascending order, leaving src untouched.
+func Float32Sorted(src []float32) []float32 {
+	dst := make([]float32, len(src))
+	float32SortInto(dst, src, defaultSorter())
+	return dst
+}
+
+// Float32SortedInto writes src sorted in ascending order to dst, leaving src untouched.
+// dst must have the length of src and must either be src or not overlap it.
+func Float32SortedInto(dst, src []float32) {
+	float32SortInto(dst, src, defaultSorter())
+}
 
-	copy(data, chunks[0])
-	if reverse {
-		float32Reverse(data)
+// Float32Sorted returns a copy of src sorted in ascending order using the Sorter's configuration.
+// It returns the errors of Float32Asc, leaving src untouched.
+func (x *Sorter) Float32Sorted(src []float32) ([]float32, error) {
+	dst := make([]float32, len(src))
+	if err := float32SortInto(dst, src, x); err != nil {
+		return nil, err
 	}
+	return dst, nil
+}
+
+// Float32SortedInto writes src sorted in ascending order to dst using the Sorter's configuration.
+// It returns the errors of Float32Asc, leaving src untouched.
+func (x *Sorter) Float32SortedInto(dst, src []float32) error {
+	return float32SortInto(dst, src, x)
+}
+
+// Float32Lazy sorts the chunks of data in parallel and returns an Iterator over its values in
+// ascending order, merging the chunks only as far as values are requested.
+func Float32Lazy(data []float32) *Iterator[float32] {
+	return lazySort(data, Float32MinParallelSize, &float32Ops)
+}
+
+var float32Ops = typeOps[float32]{
+	less:     func(a, b float32) bool { return a < b || (a != a && b == b) },
+	sort:     sortOrdered[float32],
+	merge:    float32MergeSorted,
+	key:      float32Key,
+	keyBytes: 4,
+	reverse:  float32Reverse,
+}
+
+func float32Sort(data []float32, reverse bool, s *Sorter) error {
+	return sortSlice(data, reverse, s, Float32MinParallelSize, &float32Ops)
+}
+
+func float32SortInto(dst, src []float32, s *Sorter) error {
+	return sortSliceInto(dst, src, s, Float32MinParallelSize, &float32Ops)
 }
 
 func float32MergeSorted(a, b []float32) []float32 {
 	res := make([]float32, len(a)+len(b))
-	i, j, k := 0, 0, 0
-	for i < len(a) && j < len(b) {
-		if a[i] <= b[j] {
-			res[k] = a[i]
-			i++
-		} else {
-			res[k] = b[j]
-			j++
-		}
-		k++
-	}
-	for i < len(a) {
-		res[k] = a[i]
-		i++
-		k++
-	}
-	for j < len(b) {
-		res[k] = b[j]
-		j++
-		k++
-	}
+	float32MergeKernel(res, a, b)
 	return res
 }
 
@@ -2,98 +2,105 @@ package parsort
 
 import (
 	"sort"
-	"sync"
 )
 
 func Float64Asc(data []float64) {
-	float64Sort(data, false)
+	float64Sort(data, false, defaultSorter())
 }
 
 func Float64Desc(data []float64) {
-	float64Sort(data, true)
+	float64Sort(data, true, defaultSorter())
 }
 
-func float64Sort(data []float64, reverse bool) {
-	n := len(data)
-	if n < Float64MinParallelSize {
-		sort.Float64s(data)
-		if reverse {
-			float64Reverse(data)
-		}
-		return
-	}
+// Float64Asc sorts data in ascending order using the Sorter's configuration.
+// It returns an error when the Sorter's memory budget can't be met, leaving data untouched,
+// or a *PanicError when sorting panics.
+func (x *Sorter) Float64Asc(data []float64) error {
+	return float64Sort(data, false, x)
+}
 
-	chunkSize := (n + CoreCount - 1) / CoreCount
+// Float64Desc sorts data in descending order using the Sorter's configuration.
+// It returns an error when the Sorter's memory budget can't be met, leaving data untouched,
+// or a *PanicError when sorting panics.
+func (x *Sorter) Float64Desc(data []float64) error {
+	return float64Sort(data, true, x)
+}
 
-	chunks := make([][]float64, 0, CoreCount)
-	for i := 0; i < n; i += chunkSize {
-		end := i + chunkSize
-		if end > n {
-			end = n
-		}
-		chunks = append(chunks, data[i:end])
-	}
+// Float64AscAsync starts sorting data in ascending order in the background, see Handle for when data may be used.
+func Float64AscAsync(data []float64) *Handle {
+	return async(func() error { return float64Sort(data, false, returningSorter()) })
+}
 
-	var wg sync.WaitGroup
-	for _, chunk := range chunks {
-		wg.Add(1)
-		go func(c []float64) {
-			defer wg.Done()
-			sort.Float64s(c)
-		}(chunk)
-	}
-	wg.Wait()
-
-	// Parallel merging loop
-	for len(chunks) > 1 {
-		mergedCount := (len(chunks) + 1) / 2
-		merged := make([][]float64, mergedCount)
-
-		var mWg sync.WaitGroup
-		for i := 0; i < len(chunks); i += 2 {
-			if i+1 == len(chunks) {
-				merged[i/2] = chunks[i]
-				continue
-			}
-			mWg.Add(1)
-			go func(i int) {
-				defer mWg.Done()
-				merged[i/2] = float64MergeSorted(chunks[i], chunks[i+1])
-			}(i)
-		}
-		mWg.Wait()
-		chunks = merged
-	}
+// Float64DescAsync starts sorting data in descending order in the background, see Handle for when data may be used.
+func Float64DescAsync(data []float64) *Handle {
+	return async(func() error { return float64Sort(data, true, returningSorter()) })
+}
 
-	copy(data, chunks[0])
-	if reverse {
-		float64Reverse(data)
+// Float64AscAsync is Float64AscAsync using the Sorter's configuration, cancelled with the context of WithContext.
+func (x *Sorter) Float64AscAsync(data []float64) *Handle {
+	return async(func() error { return float64Sort(data, false, x) })
+}
+
+// Float64DescAsync is Float64DescAsync using the Sorter's configuration, cancelled with the context of WithContext.
+func (x *Sorter) Float64DescAsync(data []float64) *Handle {
+	return async(func() error { return float64Sort(data, true, x) })
+}
+
+// Float64Sorted returns a copy of src sorted in ascending order, leaving src untouched.
+func Float64Sorted(src []float64) []float64 {
+	dst := make([]float64, len(src))
+	float64SortInto(dst, src, defaultSorter())
+	return dst
+}
+
+// Float64SortedInto writes src sorted in ascending order to dst, leaving src untouched.
+// dst must have the length of src and must either be src or not overlap it.
+func Float64SortedInto(dst, src []float64) {
+	float64SortInto(dst, src, defaultSorter())
+}
+
+// Float64Sorted returns a copy of src sorted in ascending order using the Sorter's configuration.
+// It returns the errors of Float64Asc, leaving src untouched.
+func (x *Sorter) Float64Sorted(src []float64) ([]float64, error) {
+	dst := make([]float64, len(src))
+	if err := float64SortInto(dst, src, x); err != nil {
+		return nil, err
 	}
+	return dst, nil
+}
+
+// Float64SortedInto writes src sorted in ascending order to dst using the Sorter's configuration.
+// It returns the errors of Float64Asc, leaving src untouched.
+func (x *Sorter) Float64SortedInto(dst, src []float64) error {
+	return float64SortInto(dst, src, x)
+}
+
+// Float64Lazy sorts the chunks of data in parallel and returns an Iterator over its values in
+// ascending order, merging the chunks only as far as values are requested.
+func Float64Lazy(data []float64) *Iterator[float64] {
+	return lazySort(data, Float64MinParallelSize, &float64Ops)
+}
+
+var float64Ops = typeOps[float64]{
+	less:     func(a, b float64) bool { return a < b || (a != a && b == b) },
+	sort:     sort.Float64s,
+	merge:    float64MergeSorted,
+	key:      float64Key,
+	keyBytes: 8,
+	reverse:  float64Reverse,
+}
+
+func float64Sort(data []float64, reverse bool, s *Sorter) error {
+	return sortSlice(data, reverse, s, Float64MinParallelSize, &float64Ops)
+}
+
+func float64SortInto(dst, src []float64, s *Sorter) error {
+	return sortSliceInto(dst, src, s, Float64MinParallelSize, &float64Ops)
 }
 
 func float64MergeSorted(a, b []float64) []float64 {
 	res := make([]float64, len(a)+len(b))
-	i, j, k := 0, 0, 0
-	for i < len(a) && j < len(b) {
-		if a[i] <= b[j] {
-			res[k] = a[i]
-			i++
-		} else {
-			res[k] = b[j]
-			j++
-		}
-		k++
-	}
-	for i < len(a) {
-		res[k] = a[i]
-		i++
-		k++
-	}
-	for j < len(b) {
-		res[k] = b[j]
-		j++
-		k++
-	}
+	float64MergeKernel(res, a, b)
 	return res
 }
 
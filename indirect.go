@@ -0,0 +1,94 @@
+package parsort
+
+import (
+	"unsafe"
+)
+
+// indexShape is the shape of the index arrays sorted by sortIndirect.
+var indexShape = memShape{size: int(unsafe.Sizeof(int(0)))}
+
+// indexBytes is the size of the index array of n elements.
+func indexBytes(n int) int64 {
+	return int64(n)*int64(indexShape.size) + pageBytes
+}
+
+// indirect reports whether x sorts n structs of size bytes through an index array.
+// Sequential sorts move elements with good locality while sorting indexes compares elements
+// in random order, only parallel sorts, whose merge levels move every element again, benefit.
+// It doesn't either when the index array alone would exhaust the memory budget.
+func (x *Sorter) indirect(size, n int) bool {
+	if x.indirectSize <= 0 || size < x.indirectSize || n < StructMinParallelSize || cores() <= 1 || x.strategy == Sequential {
+		return false
+	}
+	return x.maxExtraBytes <= 0 || indexBytes(n) < x.maxExtraBytes
+}
+
+// planShape returns the shape x sorts n elements of kind with, and the size of the index
+// array when they are sorted indirectly.
+func (x *Sorter) planShape(kind Kind, n int) (memShape, int64) {
+	if kind.shape.structs && x.indirect(kind.shape.size, n) {
+		return indexShape, indexBytes(n)
+	}
+	return kind.shape, 0
+}
+
+// sortIndirect sorts the indexes of data by less following the configuration of s, then moves
+// every element once into place. data is untouched when sorting the indexes fails.
+func sortIndirect[T any](data []T, less func(a, b T) bool, stable bool, s *Sorter) error {
+	var hash uint64
+	if s.verify {
+		hash = multisetHash(data)
+	}
+	perm := make([]int, len(data))
+	for i := range perm {
+		perm[i] = i
+	}
+	var d Decision
+	ops := structOps(func(a, b int) bool {
+		return less(data[a], data[b])
+	}, stable)
+	ops.indirect = true
+	ops.name = typeName[T]()
+	ops.decided = &d
+	if err := sortSlice(perm, false, withoutVerify(s), StructMinParallelSize, ops); err != nil {
+		return err
+	}
+	applyPermutation(data, perm)
+	if s.verify {
+		reportUnverified(s, ops.name, d, firstUnsorted(data, less), multisetHash(data) == hash)
+	}
+	return nil
+}
+
+// withoutVerify returns s, or a copy of s with Verify disabled when it is enabled, for the sorts
+// of index arrays that are verified on the data they index instead.
+func withoutVerify(s *Sorter) *Sorter {
+	if !s.verify {
+		return s
+	}
+	c := *s
+	c.verify = false
+	return &c
+}
+
+// applyPermutation moves data[perm[i]] to data[i] for every i, following the cycles of perm so that
+// every element is moved once, plus one temporary copy per cycle. perm is reset to the identity.
+func applyPermutation[T any](data []T, perm []int) {
+	for i := range perm {
+		if perm[i] == i {
+			continue
+		}
+		tmp := data[i]
+		j := i
+		for {
+			k := perm[j]
+			perm[j] = j
+			if k == i {
+				data[j] = tmp
+				break
+			}
+			data[j] = data[k]
+			j = k
+		}
+	}
+}
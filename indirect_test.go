@@ -0,0 +1,213 @@
+package parsort
+
+import (
+	"errors"
+	"math/rand"
+	"sort"
+	"sync/atomic"
+	"testing"
+)
+
+type wide struct {
+	Key     int
+	Name    *string
+	Payload [60]int
+}
+
+func genWide(n int) []wide {
+	data := make([]wide, n)
+	for i := range data {
+		name := genStrings(1)[0]
+		data[i].Key = rand.Intn(n / 2)
+		data[i].Name = &name
+		data[i].Payload[0] = i
+	}
+	return data
+}
+
+func wideLess(a, b wide) bool { return a.Key < b.Key }
+
+func TestIndirect(t *testing.T) {
+	withCoreCount(t, 4)
+	for _, st := range strategies {
+		s := NewSorter().SetStrategy(st).SetVerify(true, func(err *VerifyError) {
+			t.Errorf("%s: %v", st, err)
+		})
+		data := genWide(50000)
+		if err := StructAscStableWith(s, data, wideLess); err != nil {
+			t.Fatalf("%s: %v", st, err)
+		}
+		if s.LastDecision().Indirect != (st != Sequential) {
+			t.Errorf("%s: unexpected decision %+v", st, s.LastDecision())
+		}
+		for i := 1; i < len(data); i++ {
+			if data[i-1].Key > data[i].Key || (data[i-1].Key == data[i].Key && data[i-1].Payload[0] > data[i].Payload[0]) {
+				t.Fatalf("%s: not stably sorted at %d", st, i)
+			}
+		}
+	}
+}
+
+func TestIndirect_ObserveAndVerify(t *testing.T) {
+	withCoreCount(t, 4)
+	rec := &recorder{}
+	var got *VerifyError
+	s := NewSorter().SetObserver(rec).SetVerify(true, func(err *VerifyError) {
+		got = err
+	})
+	if err := StructAscWith(s, genWide(50000), wideLess); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if got != nil {
+		t.Fatalf("unexpected failure: %v", got)
+	}
+	for _, e := range rec.events {
+		if e.Type != "parsort.wide" {
+			t.Fatalf("expected events about parsort.wide, got %+v", e)
+		}
+	}
+
+	// less turns around while the indexes are sorted, the data ends up out of order.
+	var calls int64
+	StructAscWith(s, genWide(50000), func(a, b wide) bool {
+		if atomic.AddInt64(&calls, 1) > 200000 {
+			return a.Key > b.Key
+		}
+		return a.Key < b.Key
+	})
+	if got == nil || got.Type != "parsort.wide" || got.N != 50000 || got.Unsorted < 0 || got.NotPermutation {
+		t.Fatalf("expected an out of order failure about parsort.wide, got %v", got)
+	}
+}
+
+func TestIndirect_Override(t *testing.T) {
+	withCoreCount(t, 4)
+	s := NewSorter().SetIndirectSize(0)
+	data := genWide(20000)
+	StructAscWith(s, data, wideLess)
+	if s.LastDecision().Indirect {
+		t.Errorf("indirect sort with indirect sorting disabled")
+	}
+
+	people := genPeople(20000)
+	s.SetIndirectSize(1)
+	StructAscWith(s, people, func(a, b person) bool { return a.Age < b.Age })
+	if !s.LastDecision().Indirect || !isSortedAsc(people) {
+		t.Errorf("expected small structs to be sorted indirectly, got %+v", s.LastDecision())
+	}
+}
+
+func TestIndirect_Plan(t *testing.T) {
+	withCoreCount(t, 4)
+	s := NewSorter()
+	p, _ := s.Explain(KindStruct[wide](), 50000)
+	StructAscWith(s, genWide(50000), wideLess)
+	d := s.LastDecision()
+	if !p.Indirect || p.Strategy != d.Strategy || p.ExtraBytes != d.ExtraBytes {
+		t.Errorf("plan %+v does not match decision %+v", p, d)
+	}
+	if b, _ := s.EstimateExtraBytes(KindStruct[wide](), 50000); b != d.ExtraBytes {
+		t.Errorf("estimate %d does not match decision %+v", b, d)
+	}
+}
+
+func TestIndirect_Panic(t *testing.T) {
+	withCoreCount(t, 4)
+	data := genWide(50000)
+	orig := append([]wide(nil), data...)
+	err := StructAscWith(NewSorter(), data, func(a, b wide) bool {
+		if a.Payload[0] == 7 {
+			panic("boom")
+		}
+		return a.Key < b.Key
+	})
+	var pe *PanicError
+	if !errors.As(err, &pe) {
+		t.Fatalf("expected a *PanicError, got %v", err)
+	}
+	for i := range data {
+		if data[i] != orig[i] {
+			t.Fatalf("data modified at %d", i)
+		}
+	}
+}
+
+func TestIndirect_SingleCore(t *testing.T) {
+	withCoreCount(t, 1)
+	s := NewSorter()
+	data := genWide(20000)
+	StructAscWith(s, data, wideLess)
+	if s.LastDecision().Indirect {
+		t.Errorf("sequential sorts must move elements directly")
+	}
+}
+
+func TestApplyPermutation(t *testing.T) {
+	data := genInts(1000)
+	perm := rand.Perm(len(data))
+	expected := make([]int, len(data))
+	for i, p := range perm {
+		expected[i] = data[p]
+	}
+	applyPermutation(data, perm)
+	if !intSlicesEqual(data, expected) {
+		t.Errorf("permutation applied incorrectly")
+	}
+	if !sort.IntsAreSorted(perm) {
+		t.Errorf("perm not reset to the identity")
+	}
+}
+
+type elem64 struct {
+	Key     int
+	Payload [7]int
+}
+
+type elem256 struct {
+	Key     int
+	Payload [31]int
+}
+
+type elem1024 struct {
+	Key     int
+	Payload [127]int
+}
+
+// benchmarkIndirect compares sorting structs directly and through indexes. Sorts on a single
+// core are always direct, run with more than one core to compare both paths.
+func benchmarkIndirect[T any](b *testing.B, key func(*T) *int) {
+	src := make([]T, 200000)
+	for i := range src {
+		*key(&src[i]) = rand.Int()
+	}
+	data := make([]T, len(src))
+	less := func(a, b T) bool { return *key(&a) < *key(&b) }
+	for _, mode := range []struct {
+		name string
+		size int
+	}{{"Direct", 0}, {"Indirect", 1}} {
+		s := NewSorter().SetIndirectSize(mode.size)
+		b.Run(mode.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				copy(data, src)
+				StructAscWith(s, data, less)
+			}
+		})
+	}
+}
+
+func BenchmarkIndirect64(b *testing.B) {
+	benchmarkIndirect(b, func(e *elem64) *int { return &e.Key })
+}
+
+func BenchmarkIndirect256(b *testing.B) {
+	benchmarkIndirect(b, func(e *elem256) *int { return &e.Key })
+}
+
+func BenchmarkIndirect1024(b *testing.B) {
+	benchmarkIndirect(b, func(e *elem1024) *int { return &e.Key })
+}
+
+func BenchmarkIndirectPointers(b *testing.B) {
+	benchmarkIndirect(b, func(e *wide) *int { return &e.Key })
+}
@@ -0,0 +1,137 @@
+package parsort
+
+import (
+	"sync"
+)
+
+// inPlaceMinParallel is the smallest range a rotation merge hands to another goroutine.
+const inPlaceMinParallel = 1 << 14
+
+// inPlaceMergeSort sorts the chunks in place and merges them pairwise with the rotation based
+// SymMerge algorithm used by sort.Stable, so no merge buffer is allocated.
+func inPlaceMergeSort[T any](data []T, ops *typeOps[T]) {
+	chunks := ops.split(len(data))
+
+	runMergeTree(len(chunks), ops.trace, func(i, _ int) {
+		ops.sort(data[chunks[i].start:chunks[i].end])
+	}, func(lo, mid, hi, depth int) {
+		// Nodes closer to the root have fewer concurrent siblings and may use more goroutines.
+		par := cores() >> uint(depth)
+		symMerge(data, chunks[lo].start, chunks[mid].start, chunks[hi-1].end, par, ops.less)
+	})
+}
+
+// symMerge merges the sorted ranges data[a:m] and data[m:b] in place.
+// It follows sort.Stable's SymMerge and hands one of the two independent halves
+// to a new goroutine while par allows for more than one and the MaxWorkers budget isn't exhausted.
+func symMerge[T any](data []T, a, m, b, par int, less func(a, b T) bool) {
+	if m-a == 1 {
+		i, j := m, b
+		for i < j {
+			h := int(uint(i+j) >> 1)
+			if less(data[h], data[a]) {
+				i = h + 1
+			} else {
+				j = h
+			}
+		}
+		for k := a; k < i-1; k++ {
+			data[k], data[k+1] = data[k+1], data[k]
+		}
+		return
+	}
+
+	if b-m == 1 {
+		i, j := a, m
+		for i < j {
+			h := int(uint(i+j) >> 1)
+			if !less(data[m], data[h]) {
+				i = h + 1
+			} else {
+				j = h
+			}
+		}
+		for k := m; k > i; k-- {
+			data[k], data[k-1] = data[k-1], data[k]
+		}
+		return
+	}
+
+	mid := int(uint(a+b) >> 1)
+	n := mid + m
+	var start, r int
+	if m > mid {
+		start = n - b
+		r = mid
+	} else {
+		start = a
+		r = m
+	}
+	p := n - 1
+
+	for start < r {
+		c := int(uint(start+r) >> 1)
+		if !less(data[p-c], data[c]) {
+			start = c + 1
+		} else {
+			r = c
+		}
+	}
+
+	end := n - start
+	if start < m && m < end {
+		rotate(data, start, m, end)
+	}
+
+	left := a < start && start < mid
+	right := mid < end && end < b
+	if left && right && par > 1 && b-a >= inPlaceMinParallel && acquireWorkers(1) == 1 {
+		var wg sync.WaitGroup
+		var failure *PanicError
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer releaseWorkers(1)
+			defer func() {
+				if r := recover(); r != nil {
+					failure = newPanicError(r)
+				}
+			}()
+			symMerge(data, a, start, mid, par/2, less)
+		}()
+		symMerge(data, mid, end, b, par-par/2, less)
+		wg.Wait()
+		if failure != nil {
+			panic(failure)
+		}
+		return
+	}
+	if left {
+		symMerge(data, a, start, mid, par, less)
+	}
+	if right {
+		symMerge(data, mid, end, b, par, less)
+	}
+}
+
+// rotate rotates the two consecutive blocks data[a:m] and data[m:b] using block swaps.
+func rotate[T any](data []T, a, m, b int) {
+	i := m - a
+	j := b - m
+	for i != j {
+		if i > j {
+			swapRange(data, m-i, m, j)
+			i -= j
+		} else {
+			swapRange(data, m-i, m+j-i, i)
+			j -= i
+		}
+	}
+	swapRange(data, m-i, m, i)
+}
+
+func swapRange[T any](data []T, a, b, n int) {
+	for i := 0; i < n; i++ {
+		data[a+i], data[b+i] = data[b+i], data[a+i]
+	}
+}
@@ -2,98 +2,105 @@ package parsort
 
 import (
 	"sort"
-	"sync"
 )
 
 func IntAsc(data []int) {
-	intSort(data, false)
+	intSort(data, false, defaultSorter())
 }
 
 func IntDesc(data []int) {
-	intSort(data, true)
+	intSort(data, true, defaultSorter())
 }
 
-func intSort(data []int, reverse bool) {
-	n := len(data)
-	if n < IntMinParallelSize {
-		sort.Ints(data)
-		if reverse {
-			intReverse(data)
-		}
-		return
-	}
+// IntAsc sorts data in ascending order using the Sorter's configuration.
+// It returns an error when the Sorter's memory budget can't be met, leaving data untouched,
+// or a *PanicError when sorting panics.
+func (x *Sorter) IntAsc(data []int) error {
+	return intSort(data, false, x)
+}
 
-	chunkSize := (n + CoreCount - 1) / CoreCount
+// IntDesc sorts data in descending order using the Sorter's configuration.
+// It returns an error when the Sorter's memory budget can't be met, leaving data untouched,
+// or a *PanicError when sorting panics.
+func (x *Sorter) IntDesc(data []int) error {
+	return intSort(data, true, x)
+}
 
-	chunks := make([][]int, 0, CoreCount)
-	for i := 0; i < n; i += chunkSize {
-		end := i + chunkSize
-		if end > n {
-			end = n
-		}
-		chunks = append(chunks, data[i:end])
-	}
+// IntAscAsync starts sorting data in ascending order in the background, see Handle for when data may be used.
+func IntAscAsync(data []int) *Handle {
+	return async(func() error { return intSort(data, false, returningSorter()) })
+}
 
-	var wg sync.WaitGroup
-	for _, chunk := range chunks {
-		wg.Add(1)
-		go func(c []int) {
-			defer wg.Done()
-			sort.Ints(c)
-		}(chunk)
-	}
-	wg.Wait()
-
-	// Parallel merging loop
-	for len(chunks) > 1 {
-		mergedCount := (len(chunks) + 1) / 2
-		merged := make([][]int, mergedCount)
-
-		var mWg sync.WaitGroup
-		for i := 0; i < len(chunks); i += 2 {
-			if i+1 == len(chunks) {
-				merged[i/2] = chunks[i]
-				continue
-			}
-			mWg.Add(1)
-			go func(i int) {
-				defer mWg.Done()
-				merged[i/2] = intMergeSorted(chunks[i], chunks[i+1])
-			}(i)
-		}
-		mWg.Wait()
-		chunks = merged
-	}
+// IntDescAsync starts sorting data in descending order in the background, see Handle for when data may be used.
+func IntDescAsync(data []int) *Handle {
+	return async(func() error { return intSort(data, true, returningSorter()) })
+}
 
-	copy(data, chunks[0])
-	if reverse {
-		intReverse(data)
+// IntAscAsync is IntAscAsync using the Sorter's configuration, cancelled with the context of WithContext.
+func (x *Sorter) IntAscAsync(data []int) *Handle {
+	return async(func() error { return intSort(data, false, x) })
+}
+
+// IntDescAsync is IntDescAsync using the Sorter's configuration, cancelled with the context of WithContext.
+func (x *Sorter) IntDescAsync(data []int) *Handle {
+	return async(func() error { return intSort(data, true, x) })
+}
+
+// IntSorted returns a copy of src sorted in ascending order, leaving src untouched.
+func IntSorted(src []int) []int {
+	dst := make([]int, len(src))
+	intSortInto(dst, src, defaultSorter())
+	return dst
+}
+
+// IntSortedInto writes src sorted in ascending order to dst, leaving src untouched.
+// dst must have the length of src and must either be src or not overlap it.
+func IntSortedInto(dst, src []int) {
+	intSortInto(dst, src, defaultSorter())
+}
+
+// IntSorted returns a copy of src sorted in ascending order using the Sorter's configuration.
+// It returns the errors of IntAsc, leaving src untouched.
+func (x *Sorter) IntSorted(src []int) ([]int, error) {
+	dst := make([]int, len(src))
+	if err := intSortInto(dst, src, x); err != nil {
+		return nil, err
 	}
+	return dst, nil
+}
+
+// IntSortedInto writes src sorted in ascending order to dst using the Sorter's configuration.
+// It returns the errors of IntAsc, leaving src untouched.
+func (x *Sorter) IntSortedInto(dst, src []int) error {
+	return intSortInto(dst, src, x)
+}
+
+// IntLazy sorts the chunks of data in parallel and returns an Iterator over its values in
+// ascending order, merging the chunks only as far as values are requested.
+func IntLazy(data []int) *Iterator[int] {
+	return lazySort(data, IntMinParallelSize, &intOps)
+}
+
+var intOps = typeOps[int]{
+	less:     func(a, b int) bool { return a < b },
+	sort:     sort.Ints,
+	merge:    intMergeSorted,
+	key:      func(v int) uint64 { return uint64(v) ^ 1<<63 },
+	keyBytes: 8,
+	reverse:  intReverse,
+}
+
+func intSort(data []int, reverse bool, s *Sorter) error {
+	return sortSlice(data, reverse, s, IntMinParallelSize, &intOps)
+}
+
+func intSortInto(dst, src []int, s *Sorter) error {
+	return sortSliceInto(dst, src, s, IntMinParallelSize, &intOps)
 }
 
 func intMergeSorted(a, b []int) []int {
 	res := make([]int, len(a)+len(b))
-	i, j, k := 0, 0, 0
-	for i < len(a) && j < len(b) {
-		if a[i] <= b[j] {
-			res[k] = a[i]
-			i++
-		} else {
-			res[k] = b[j]
-			j++
-		}
-		k++
-	}
-	for i < len(a) {
-		res[k] = a[i]
-		i++
-		k++
-	}
-	for j < len(b) {
-		res[k] = b[j]
-		j++
-		k++
-	}
+	intMergeKernel(res, a, b)
 	return res
 }
 
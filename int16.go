@@ -1,77 +1,97 @@
 package parsort
 
-import (
-	"sort"
-	"sync"
-)
-
 func Int16Asc(data []int16) {
-	int16Sort(data, false)
+	int16Sort(data, false, defaultSorter())
 }
 
 func Int16Desc(data []int16) {
-	int16Sort(data, true)
+	int16Sort(data, true, defaultSorter())
 }
 
-func int16Sort(data []int16, reverse bool) {
-	n := len(data)
-	if n < Int16MinParallelSize {
-		sort.Slice(data, func(i, j int) bool {
-			return data[i] < data[j]
-		})
-		if reverse {
-			int16Reverse(data)
-		}
-		return
-	}
+// Int16Asc sorts data in ascending order using the Sorter's configuration.
+// It returns an error when the Sorter's memory budget can't be met, leaving data untouched,
+// or a *PanicError when sorting panics.
+func (x *Sorter) Int16Asc(data []int16) error {
+	return int16Sort(data, false, x)
+}
 
-	chunkSize := (n + CoreCount - 1) / CoreCount
+// Int16Desc sorts data in descending order using the Sorter's configuration.
+// It returns an error when the Sorter's memory budget can't be met, leaving data untouched,
+// or a *PanicError when sorting panics.
+func (x *Sorter) Int16Desc(data []int16) error {
+	return int16Sort(data, true, x)
+}
 
-	chunks := make([][]int16, 0, CoreCount)
-	for i := 0; i < n; i += chunkSize {
-		end := i + chunkSize
-		if end > n {
-			end = n
-		}
-		chunks = append(chunks, data[i:end])
-	}
+// Int16AscAsync starts sorting data in ascending order in the background, see Handle for when data may be used.
+func Int16AscAsync(data []int16) *Handle {
+	return async(func() error { return int16Sort(data, false, returningSorter()) })
+}
 
-	var wg sync.WaitGroup
-	for _, chunk := range chunks {
-		wg.Add(1)
-		go func(c []int16) {
-			defer wg.Done()
-			sort.Slice(c, func(i, j int) bool {
-				return c[i] < c[j]
-			})
-		}(chunk)
-	}
-	wg.Wait()
-
-	for len(chunks) > 1 {
-		mergedCount := (len(chunks) + 1) / 2
-		merged := make([][]int16, mergedCount)
-
-		var mWg sync.WaitGroup
-		for i := 0; i < len(chunks); i += 2 {
-			if i+1 == len(chunks) {
-				merged[i/2] = chunks[i]
-				continue
-			}
-			mWg.Add(1)
-			go func(i int) {
-				defer mWg.Done()
-				merged[i/2] = int16MergeSorted(chunks[i], chunks[i+1])
-			}(i)
-		}
-		mWg.Wait()
-		chunks = merged
-	}
+// Int16DescAsync starts sorting data in descending order in the background, see Handle for when data may be used.
+func Int16DescAsync(data []int16) *Handle {
+	return async(func() error { return int16Sort(data, true, returningSorter()) })
+}
+
+// Int16AscAsync is Int16AscAsync using the Sorter's configuration, cancelled with the context of WithContext.
+func (x *Sorter) Int16AscAsync(data []int16) *Handle {
+	return async(func() error { return int16Sort(data, false, x) })
+}
+
+// Int16DescAsync is Int16DescAsync using the Sorter's configuration, cancelled with the context of WithContext.
+func (x *Sorter) Int16DescAsync(data []int16) *Handle {
+	return async(func() error { return int16Sort(data, true, x) })
+}
+
+// Int16Sorted returns a copy of src sorted in ascending order, leaving src untouched.
+func Int16Sorted(src []int16) []int16 {
+	dst := make([]int16, len(src))
+	int16SortInto(dst, src, defaultSorter())
+	return dst
+}
+
+// Int16SortedInto writes src sorted in ascending order to dst, leaving src untouched.
+// dst must have the length of src and must either be src or not overlap it.
+func Int16SortedInto(dst, src []int16) {
+	int16SortInto(dst, src, defaultSorter())
+}
 
-	copy(data, chunks[0])
-	if reverse {
-		int16Reverse(data)
+// Int16Sorted returns a copy of src sorted in ascending order using the Sorter's configuration.
+// It returns the errors of Int16Asc, leaving src untouched.
+func (x *Sorter) Int16Sorted(src []int16) ([]int16, error) {
+	dst := make([]int16, len(src))
+	if err := int16SortInto(dst, src, x); err != nil {
+		return nil, err
 	}
+	return dst, nil
+}
+
+// Int16SortedInto writes src sorted in ascending order to dst using the Sorter's configuration.
+// It returns the errors of Int16Asc, leaving src untouched.
+func (x *Sorter) Int16SortedInto(dst, src []int16) error {
+	return int16SortInto(dst, src, x)
+}
+
+// Int16Lazy sorts the chunks of data in parallel and returns an Iterator over its values in
+// ascending order, merging the chunks only as far as values are requested.
+func Int16Lazy(data []int16) *Iterator[int16] {
+	return lazySort(data, Int16MinParallelSize, &int16Ops)
+}
+
+var int16Ops = typeOps[int16]{
+	less:     func(a, b int16) bool { return a < b },
+	sort:     sortOrdered[int16],
+	merge:    int16MergeSorted,
+	key:      func(v int16) uint64 { return uint64(uint16(v) ^ 0x8000) },
+	keyBytes: 2,
+	reverse:  int16Reverse,
+}
+
+func int16Sort(data []int16, reverse bool, s *Sorter) error {
+	return sortSlice(data, reverse, s, Int16MinParallelSize, &int16Ops)
+}
+
+func int16SortInto(dst, src []int16, s *Sorter) error {
+	return sortSliceInto(dst, src, s, Int16MinParallelSize, &int16Ops)
 }
 
 func int16MergeSorted(a, b []int16) []int16 {
@@ -1,102 +1,102 @@
 package parsort
 
-import (
-	"sort"
-	"sync"
-)
-
 func Int32Asc(data []int32) {
-	int32Sort(data, false)
+	int32Sort(data, false, defaultSorter())
 }
 
 func Int32Desc(data []int32) {
-	int32Sort(data, true)
-}
-
-func int32Sort(data []int32, reverse bool) {
-	n := len(data)
-	if n < Int32MinParallelSize {
-		sort.Slice(data, func(i, j int) bool {
-			return data[i] < data[j]
-		})
-		if reverse {
-			int32Reverse(data)
-		}
-		return
-	}
+	int32Sort(data, true, defaultSorter())
+}
 
-	chunkSize := (n + CoreCount - 1) / CoreCount
+// Int32Asc sorts data in ascending order using the Sorter's configuration.
+// It returns an error when the Sorter's memory budget can't be met, leaving data untouched,
+// or a *PanicError when sorting panics.
+func (x *Sorter) Int32Asc(data []int32) error {
+	return int32Sort(data, false, x)
+}
 
-	chunks := make([][]int32, 0, CoreCount)
-	for i := 0; i < n; i += chunkSize {
-		end := i + chunkSize
-		if end > n {
-			end = n
-		}
-		chunks = append(chunks, data[i:end])
-	}
+// Int32Desc sorts data in descending order using the Sorter's configuration.
+// It returns an error when the Sorter's memory budget can't be met, leaving data untouched,
+// or a *PanicError when sorting panics.
+func (x *Sorter) Int32Desc(data []int32) error {
+	return int32Sort(data, true, x)
+}
 
-	var wg sync.WaitGroup
-	for _, chunk := range chunks {
-		wg.Add(1)
-		go func(c []int32) {
-			defer wg.Done()
-			sort.Slice(c, func(i, j int) bool {
-				return c[i] < c[j]
-			})
-		}(chunk)
-	}
-	wg.Wait()
-
-	for len(chunks) > 1 {
-		mergedCount := (len(chunks) + 1) / 2
-		merged := make([][]int32, mergedCount)
-
-		var mWg sync.WaitGroup
-		for i := 0; i < len(chunks); i += 2 {
-			if i+1 == len(chunks) {
-				merged[i/2] = chunks[i]
-				continue
-			}
-			mWg.Add(1)
-			go func(i int) {
-				defer mWg.Done()
-				merged[i/2] = int32MergeSorted(chunks[i], chunks[i+1])
-			}(i)
-		}
-		mWg.Wait()
-		chunks = merged
-	}
+// Int32AscAsync starts sorting data in ascending order in the background, see Handle for when data may be used.
+func Int32AscAsync(data []int32) *Handle {
+	return async(func() error { return int32Sort(data, false, returningSorter()) })
+}
+
+// Int32DescAsync starts sorting data in descending order in the background, see Handle for when data may be used.
+func Int32DescAsync(data []int32) *Handle {
+	return async(func() error { return int32Sort(data, true, returningSorter()) })
+}
+
+// Int32AscAsync is Int32AscAsync using the Sorter's configuration, cancelled with the context of WithContext.
+func (x *Sorter) Int32AscAsync(data []int32) *Handle {
+	return async(func() error { return int32Sort(data, false, x) })
+}
+
+// Int32DescAsync is Int32DescAsync using the Sorter's configuration, cancelled with the context of WithContext.
+func (x *Sorter) Int32DescAsync(data []int32) *Handle {
+	return async(func() error { return int32Sort(data, true, x) })
+}
+
+// Int32Sorted returns a copy of src sorted in ascending order, leaving src untouched.
+func Int32Sorted(src []int32) []int32 {
+	dst := make([]int32, len(src))
+	int32SortInto(dst, src, defaultSorter())
+	return dst
+}
+
+// Int32SortedInto writes src sorted in ascending order to dst, leaving src untouched.
+// dst must have the length of src and must either be src or not overlap it.
+func Int32SortedInto(dst, src []int32) {
+	int32SortInto(dst, src, defaultSorter())
+}
 
-	copy(data, chunks[0])
-	if reverse {
-		int32Reverse(data)
+// Int32Sorted returns a copy of src sorted in ascending order using the Sorter's configuration.
+// It returns the errors of Int32Asc, leaving src untouched.
+func (x *Sorter) Int32Sorted(src []int32) ([]int32, error) {
+	dst := make([]int32, len(src))
+	if err := int32SortInto(dst, src, x); err != nil {
+		return nil, err
 	}
+	return dst, nil
+}
+
+// Int32SortedInto writes src sorted in ascending order to dst using the Sorter's configuration.
+// It returns the errors of Int32Asc, leaving src untouched.
+func (x *Sorter) Int32SortedInto(dst, src []int32) error {
+	return int32SortInto(dst, src, x)
+}
+
+// Int32Lazy sorts the chunks of data in parallel and returns an Iterator over its values in
+// ascending order, merging the chunks only as far as values are requested.
+func Int32Lazy(data []int32) *Iterator[int32] {
+	return lazySort(data, Int32MinParallelSize, &int32Ops)
+}
+
+var int32Ops = typeOps[int32]{
+	less:     func(a, b int32) bool { return a < b },
+	sort:     sortOrdered[int32],
+	merge:    int32MergeSorted,
+	key:      func(v int32) uint64 { return uint64(uint32(v) ^ 0x80000000) },
+	keyBytes: 4,
+	reverse:  int32Reverse,
+}
+
+func int32Sort(data []int32, reverse bool, s *Sorter) error {
+	return sortSlice(data, reverse, s, Int32MinParallelSize, &int32Ops)
+}
+
+func int32SortInto(dst, src []int32, s *Sorter) error {
+	return sortSliceInto(dst, src, s, Int32MinParallelSize, &int32Ops)
 }
 
 func int32MergeSorted(a, b []int32) []int32 {
 	res := make([]int32, len(a)+len(b))
-	i, j, k := 0, 0, 0
-	for i < len(a) && j < len(b) {
-		if a[i] <= b[j] {
-			res[k] = a[i]
-			i++
-		} else {
-			res[k] = b[j]
-			j++
-		}
-		k++
-	}
-	for i < len(a) {
-		res[k] = a[i]
-		i++
-		k++
-	}
-	for j < len(b) {
-		res[k] = b[j]
-		j++
-		k++
-	}
+	int32MergeKernel(res, a, b)
 	return res
 }
 
@@ -1,102 +1,102 @@
 package parsort
 
-import (
-	"sort"
-	"sync"
-)
-
 func Int64Asc(data []int64) {
-	int64Sort(data, false)
+	int64Sort(data, false, defaultSorter())
 }
 
 func Int64Desc(data []int64) {
-	int64Sort(data, true)
-}
-
-func int64Sort(data []int64, reverse bool) {
-	n := len(data)
-	if n < Int64MinParallelSize {
-		sort.Slice(data, func(i, j int) bool {
-			return data[i] < data[j]
-		})
-		if reverse {
-			int64Reverse(data)
-		}
-		return
-	}
+	int64Sort(data, true, defaultSorter())
+}
 
-	chunkSize := (n + CoreCount - 1) / CoreCount
+// Int64Asc sorts data in ascending order using the Sorter's configuration.
+// It returns an error when the Sorter's memory budget can't be met, leaving data untouched,
+// or a *PanicError when sorting panics.
+func (x *Sorter) Int64Asc(data []int64) error {
+	return int64Sort(data, false, x)
+}
 
-	chunks := make([][]int64, 0, CoreCount)
-	for i := 0; i < n; i += chunkSize {
-		end := i + chunkSize
-		if end > n {
-			end = n
-		}
-		chunks = append(chunks, data[i:end])
-	}
+// Int64Desc sorts data in descending order using the Sorter's configuration.
+// It returns an error when the Sorter's memory budget can't be met, leaving data untouched,
+// or a *PanicError when sorting panics.
+func (x *Sorter) Int64Desc(data []int64) error {
+	return int64Sort(data, true, x)
+}
 
-	var wg sync.WaitGroup
-	for _, chunk := range chunks {
-		wg.Add(1)
-		go func(c []int64) {
-			defer wg.Done()
-			sort.Slice(c, func(i, j int) bool {
-				return c[i] < c[j]
-			})
-		}(chunk)
-	}
-	wg.Wait()
-
-	for len(chunks) > 1 {
-		mergedCount := (len(chunks) + 1) / 2
-		merged := make([][]int64, mergedCount)
-
-		var mWg sync.WaitGroup
-		for i := 0; i < len(chunks); i += 2 {
-			if i+1 == len(chunks) {
-				merged[i/2] = chunks[i]
-				continue
-			}
-			mWg.Add(1)
-			go func(i int) {
-				defer mWg.Done()
-				merged[i/2] = int64MergeSorted(chunks[i], chunks[i+1])
-			}(i)
-		}
-		mWg.Wait()
-		chunks = merged
-	}
+// Int64AscAsync starts sorting data in ascending order in the background, see Handle for when data may be used.
+func Int64AscAsync(data []int64) *Handle {
+	return async(func() error { return int64Sort(data, false, returningSorter()) })
+}
+
+// Int64DescAsync starts sorting data in descending order in the background, see Handle for when data may be used.
+func Int64DescAsync(data []int64) *Handle {
+	return async(func() error { return int64Sort(data, true, returningSorter()) })
+}
+
+// Int64AscAsync is Int64AscAsync using the Sorter's configuration, cancelled with the context of WithContext.
+func (x *Sorter) Int64AscAsync(data []int64) *Handle {
+	return async(func() error { return int64Sort(data, false, x) })
+}
+
+// Int64DescAsync is Int64DescAsync using the Sorter's configuration, cancelled with the context of WithContext.
+func (x *Sorter) Int64DescAsync(data []int64) *Handle {
+	return async(func() error { return int64Sort(data, true, x) })
+}
+
+// Int64Sorted returns a copy of src sorted in ascending order, leaving src untouched.
+func Int64Sorted(src []int64) []int64 {
+	dst := make([]int64, len(src))
+	int64SortInto(dst, src, defaultSorter())
+	return dst
+}
+
+// Int64SortedInto writes src sorted in ascending order to dst, leaving src untouched.
+// dst must have the length of src and must either be src or not overlap it.
+func Int64SortedInto(dst, src []int64) {
+	int64SortInto(dst, src, defaultSorter())
+}
 
-	copy(data, chunks[0])
-	if reverse {
-		int64Reverse(data)
+// Int64Sorted returns a copy of src sorted in ascending order using the Sorter's configuration.
+// It returns the errors of Int64Asc, leaving src untouched.
+func (x *Sorter) Int64Sorted(src []int64) ([]int64, error) {
+	dst := make([]int64, len(src))
+	if err := int64SortInto(dst, src, x); err != nil {
+		return nil, err
 	}
+	return dst, nil
+}
+
+// Int64SortedInto writes src sorted in ascending order to dst using the Sorter's configuration.
+// It returns the errors of Int64Asc, leaving src untouched.
+func (x *Sorter) Int64SortedInto(dst, src []int64) error {
+	return int64SortInto(dst, src, x)
+}
+
+// Int64Lazy sorts the chunks of data in parallel and returns an Iterator over its values in
+// ascending order, merging the chunks only as far as values are requested.
+func Int64Lazy(data []int64) *Iterator[int64] {
+	return lazySort(data, Int64MinParallelSize, &int64Ops)
+}
+
+var int64Ops = typeOps[int64]{
+	less:     func(a, b int64) bool { return a < b },
+	sort:     sortOrdered[int64],
+	merge:    int64MergeSorted,
+	key:      func(v int64) uint64 { return uint64(v) ^ 1<<63 },
+	keyBytes: 8,
+	reverse:  int64Reverse,
+}
+
+func int64Sort(data []int64, reverse bool, s *Sorter) error {
+	return sortSlice(data, reverse, s, Int64MinParallelSize, &int64Ops)
+}
+
+func int64SortInto(dst, src []int64, s *Sorter) error {
+	return sortSliceInto(dst, src, s, Int64MinParallelSize, &int64Ops)
 }
 
 func int64MergeSorted(a, b []int64) []int64 {
 	res := make([]int64, len(a)+len(b))
-	i, j, k := 0, 0, 0
-	for i < len(a) && j < len(b) {
-		if a[i] <= b[j] {
-			res[k] = a[i]
-			i++
-		} else {
-			res[k] = b[j]
-			j++
-		}
-		k++
-	}
-	for i < len(a) {
-		res[k] = a[i]
-		i++
-		k++
-	}
-	for j < len(b) {
-		res[k] = b[j]
-		j++
-		k++
-	}
+	int64MergeKernel(res, a, b)
 	return res
 }
 
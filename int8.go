@@ -1,77 +1,97 @@
 package parsort
 
-import (
-	"sort"
-	"sync"
-)
-
 func Int8Asc(data []int8) {
-	int8Sort(data, false)
+	int8Sort(data, false, defaultSorter())
 }
 
 func Int8Desc(data []int8) {
-	int8Sort(data, true)
+	int8Sort(data, true, defaultSorter())
 }
 
-func int8Sort(data []int8, reverse bool) {
-	n := len(data)
-	if n < Int8MinParallelSize {
-		sort.Slice(data, func(i, j int) bool {
-			return data[i] < data[j]
-		})
-		if reverse {
-			int8Reverse(data)
-		}
-		return
-	}
+// Int8Asc sorts data in ascending order using the Sorter's configuration.
+// It returns an error when the Sorter's memory budget can't be met, leaving data untouched,
+// or a *PanicError when sorting panics.
+func (x *Sorter) Int8Asc(data []int8) error {
+	return int8Sort(data, false, x)
+}
 
-	chunkSize := (n + CoreCount - 1) / CoreCount
+// Int8Desc sorts data in descending order using the Sorter's configuration.
+// It returns an error when the Sorter's memory budget can't be met, leaving data untouched,
+// or a *PanicError when sorting panics.
+func (x *Sorter) Int8Desc(data []int8) error {
+	return int8Sort(data, true, x)
+}
 
-	chunks := make([][]int8, 0, CoreCount)
-	for i := 0; i < n; i += chunkSize {
-		end := i + chunkSize
-		if end > n {
-			end = n
-		}
-		chunks = append(chunks, data[i:end])
-	}
+// Int8AscAsync starts sorting data in ascending order in the background, see Handle for when data may be used.
+func Int8AscAsync(data []int8) *Handle {
+	return async(func() error { return int8Sort(data, false, returningSorter()) })
+}
 
-	var wg sync.WaitGroup
-	for _, chunk := range chunks {
-		wg.Add(1)
-		go func(c []int8) {
-			defer wg.Done()
-			sort.Slice(c, func(i, j int) bool {
-				return c[i] < c[j]
-			})
-		}(chunk)
-	}
-	wg.Wait()
-
-	for len(chunks) > 1 {
-		mergedCount := (len(chunks) + 1) / 2
-		merged := make([][]int8, mergedCount)
-
-		var mWg sync.WaitGroup
-		for i := 0; i < len(chunks); i += 2 {
-			if i+1 == len(chunks) {
-				merged[i/2] = chunks[i]
-				continue
-			}
-			mWg.Add(1)
-			go func(i int) {
-				defer mWg.Done()
-				merged[i/2] = int8MergeSorted(chunks[i], chunks[i+1])
-			}(i)
-		}
-		mWg.Wait()
-		chunks = merged
-	}
+// Int8DescAsync starts sorting data in descending order in the background, see Handle for when data may be used.
+func Int8DescAsync(data []int8) *Handle {
+	return async(func() error { return int8Sort(data, true, returningSorter()) })
+}
+
+// Int8AscAsync is Int8AscAsync using the Sorter's configuration, cancelled with the context of WithContext.
+func (x *Sorter) Int8AscAsync(data []int8) *Handle {
+	return async(func() error { return int8Sort(data, false, x) })
+}
+
+// Int8DescAsync is Int8DescAsync using the Sorter's configuration, cancelled with the context of WithContext.
+func (x *Sorter) Int8DescAsync(data []int8) *Handle {
+	return async(func() error { return int8Sort(data, true, x) })
+}
+
+// Int8Sorted returns a copy of src sorted in ascending order, leaving src untouched.
+func Int8Sorted(src []int8) []int8 {
+	dst := make([]int8, len(src))
+	int8SortInto(dst, src, defaultSorter())
+	return dst
+}
+
+// Int8SortedInto writes src sorted in ascending order to dst, leaving src untouched.
+// dst must have the length of src and must either be src or not overlap it.
+func Int8SortedInto(dst, src []int8) {
+	int8SortInto(dst, src, defaultSorter())
+}
 
-	copy(data, chunks[0])
-	if reverse {
-		int8Reverse(data)
+// Int8Sorted returns a copy of src sorted in ascending order using the Sorter's configuration.
+// It returns the errors of Int8Asc, leaving src untouched.
+func (x *Sorter) Int8Sorted(src []int8) ([]int8, error) {
+	dst := make([]int8, len(src))
+	if err := int8SortInto(dst, src, x); err != nil {
+		return nil, err
 	}
+	return dst, nil
+}
+
+// Int8SortedInto writes src sorted in ascending order to dst using the Sorter's configuration.
+// It returns the errors of Int8Asc, leaving src untouched.
+func (x *Sorter) Int8SortedInto(dst, src []int8) error {
+	return int8SortInto(dst, src, x)
+}
+
+// Int8Lazy sorts the chunks of data in parallel and returns an Iterator over its values in
+// ascending order, merging the chunks only as far as values are requested.
+func Int8Lazy(data []int8) *Iterator[int8] {
+	return lazySort(data, Int8MinParallelSize, &int8Ops)
+}
+
+var int8Ops = typeOps[int8]{
+	less:     func(a, b int8) bool { return a < b },
+	sort:     sortOrdered[int8],
+	merge:    int8MergeSorted,
+	key:      func(v int8) uint64 { return uint64(uint8(v) ^ 0x80) },
+	keyBytes: 1,
+	reverse:  int8Reverse,
+}
+
+func int8Sort(data []int8, reverse bool, s *Sorter) error {
+	return sortSlice(data, reverse, s, Int8MinParallelSize, &int8Ops)
+}
+
+func int8SortInto(dst, src []int8, s *Sorter) error {
+	return sortSliceInto(dst, src, s, Int8MinParallelSize, &int8Ops)
 }
 
 func int8MergeSorted(a, b []int8) []int8 {
@@ -0,0 +1,72 @@
+package parsort
+
+import (
+	"sort"
+)
+
+// ParallelSort sorts data in ascending order as determined by its Less method, like sort.Sort.
+// Chunks of data are sorted concurrently on disjoint index ranges, their sorted orders are merged
+// as arrays of indexes, and the resulting permutation is applied once with Swap. Less and Swap
+// must be safe to call concurrently on disjoint index ranges, as they are for slices and for
+// struct-of-arrays tables. Below StructMinParallelSize, data is sorted with sort.Sort.
+func ParallelSort(data sort.Interface) {
+	sortInterface(data, false)
+}
+
+// ParallelStable is ParallelSort keeping equal elements in their original order, like sort.Stable.
+func ParallelStable(data sort.Interface) {
+	sortInterface(data, true)
+}
+
+// rangeInterface is the index range [off, off+n) of an interface, used to sort one chunk.
+type rangeInterface struct {
+	data sort.Interface
+	off  int
+	n    int
+}
+
+func (x rangeInterface) Len() int           { return x.n }
+func (x rangeInterface) Less(i, j int) bool { return x.data.Less(x.off+i, x.off+j) }
+func (x rangeInterface) Swap(i, j int)      { x.data.Swap(x.off+i, x.off+j) }
+
+func sortInterface(data sort.Interface, stable bool) {
+	n := data.Len()
+	sortRange := sort.Sort
+	if stable {
+		sortRange = sort.Stable
+	}
+	if n < StructMinParallelSize || cores() <= 1 {
+		sortRange(data)
+		return
+	}
+
+	// Every leaf sorts its range of data in place and stands for it with its indexes, which are
+	// already in order. The merges only compare, data is not moved until the permutation is known.
+	chunks := splitTasks(n)
+	perm := make([]int, n)
+	buffer := make([]int, n)
+	ops := &typeOps[int]{less: data.Less}
+	pingPongTree(perm, buffer, chunks, ops, func(i int, dst []int) {
+		c := chunks[i]
+		sortRange(rangeInterface{data: data, off: c.start, n: c.end - c.start})
+		for j := c.start; j < c.end; j++ {
+			dst[j] = j
+		}
+	})
+	applySwaps(data, perm)
+}
+
+// applySwaps moves the element at perm[i] to i for every i using Swap, following the cycles of
+// perm so that every element is swapped into place once. perm is reset to the identity.
+func applySwaps(data sort.Interface, perm []int) {
+	for i := range perm {
+		j := i
+		for perm[j] != i {
+			k := perm[j]
+			data.Swap(j, k)
+			perm[j] = j
+			j = k
+		}
+		perm[j] = j
+	}
+}
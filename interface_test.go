@@ -0,0 +1,135 @@
+package parsort
+
+import (
+	"errors"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// table is a struct-of-arrays container implementing sort.Interface.
+type table struct {
+	keys []int
+	rows []int
+	tags []string
+}
+
+func genTable(n, keys int) *table {
+	t := &table{keys: make([]int, n), rows: make([]int, n), tags: make([]string, n)}
+	for i := 0; i < n; i++ {
+		t.keys[i] = rand.Intn(keys)
+		t.rows[i] = i
+		t.tags[i] = string(rune('a' + i%26))
+	}
+	return t
+}
+
+func (x *table) Len() int           { return len(x.keys) }
+func (x *table) Less(i, j int) bool { return x.keys[i] < x.keys[j] }
+func (x *table) Swap(i, j int) {
+	x.keys[i], x.keys[j] = x.keys[j], x.keys[i]
+	x.rows[i], x.rows[j] = x.rows[j], x.rows[i]
+	x.tags[i], x.tags[j] = x.tags[j], x.tags[i]
+}
+
+func checkTable(t *testing.T, tab *table, stable bool) {
+	t.Helper()
+	seen := make([]bool, tab.Len())
+	for i := 0; i < tab.Len(); i++ {
+		r := tab.rows[i]
+		if seen[r] || tab.tags[i] != string(rune('a'+r%26)) {
+			t.Fatalf("row %d moved inconsistently", r)
+		}
+		seen[r] = true
+		if i == 0 {
+			continue
+		}
+		if tab.keys[i-1] > tab.keys[i] {
+			t.Fatalf("not sorted at %d", i)
+		}
+		if stable && tab.keys[i-1] == tab.keys[i] && tab.rows[i-1] > tab.rows[i] {
+			t.Fatalf("not stable at %d", i)
+		}
+	}
+}
+
+func TestParallelSort(t *testing.T) {
+	withCoreCount(t, 4)
+	for _, n := range []int{0, 1, 100, 50000} {
+		tab := genTable(n, n+1)
+		ParallelSort(tab)
+		checkTable(t, tab, false)
+	}
+}
+
+func TestParallelStable(t *testing.T) {
+	withCoreCount(t, 4)
+	for _, n := range []int{100, 50000} {
+		tab := genTable(n, 50)
+		ParallelStable(tab)
+		checkTable(t, tab, true)
+	}
+
+	data := genInts(30000)
+	expected := append([]int(nil), data...)
+	sort.Ints(expected)
+	ParallelStable(sort.IntSlice(data))
+	if !intSlicesEqual(data, expected) {
+		t.Errorf("ParallelStable on sort.IntSlice failed")
+	}
+}
+
+func TestParallelSort_Panic(t *testing.T) {
+	withCoreCount(t, 4)
+	defer func() {
+		var pe *PanicError
+		if err, ok := recover().(error); !ok || !errors.As(err, &pe) {
+			t.Errorf("expected a *PanicError, got %v", err)
+		}
+	}()
+	ParallelSort(panicky{genTable(50000, 100)})
+}
+
+type panicky struct{ *table }
+
+func (x panicky) Less(i, j int) bool {
+	if x.rows[i] == 42 {
+		panic("boom")
+	}
+	return x.table.Less(i, j)
+}
+
+func TestApplySwaps(t *testing.T) {
+	data := genInts(1000)
+	perm := rand.Perm(len(data))
+	expected := make([]int, len(data))
+	for i, p := range perm {
+		expected[i] = data[p]
+	}
+	applySwaps(sort.IntSlice(data), perm)
+	if !intSlicesEqual(data, expected) {
+		t.Errorf("permutation applied incorrectly")
+	}
+}
+
+func BenchmarkParallelSort(b *testing.B) {
+	src := genTable(1000000, 1000000)
+	tab := genTable(len(src.keys), 1)
+	reset := func() {
+		copy(tab.keys, src.keys)
+		copy(tab.rows, src.rows)
+		copy(tab.tags, src.tags)
+	}
+	b.Run("sort.Sort", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			reset()
+			sort.Sort(tab)
+		}
+	})
+	b.Run("ParallelSort", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			reset()
+			ParallelSort(tab)
+		}
+	})
+}
@@ -0,0 +1,164 @@
+package parsort
+
+import (
+	"sort"
+)
+
+// kWayMergeSort sorts the chunks in place and then merges all of them in a single pass.
+// The output is split into one range per core using sampled splitters, and each range
+// is produced independently with a heap based k-way merge.
+func kWayMergeSort[T any](data []T, ops *typeOps[T]) {
+	chunks := ops.split(len(data))
+	sortChunks(data, chunks, ops)
+	if len(chunks) <= 1 {
+		return
+	}
+
+	parts := len(splitChunks(len(data)))
+	splitters := kWaySplitters(data, chunks, parts, ops.less)
+
+	// bounds[p][c] is where part p starts inside chunk c.
+	bounds := make([][]int, parts+1)
+	for p := range bounds {
+		bounds[p] = make([]int, len(chunks))
+		for c, ch := range chunks {
+			switch p {
+			case 0:
+				bounds[p][c] = ch.start
+			case parts:
+				bounds[p][c] = ch.end
+			default:
+				s := splitters[p-1]
+				bounds[p][c] = ch.start + sort.Search(ch.end-ch.start, func(i int) bool {
+					return ops.less(s, data[ch.start+i])
+				})
+			}
+		}
+	}
+
+	buffer := ops.buffer(len(data))
+	outputs := make([][]T, parts)
+	inputs := make([][][]T, parts)
+	offset := 0
+	for p := 0; p < parts; p++ {
+		runs := make([][]T, len(chunks))
+		size := 0
+		for c := range chunks {
+			runs[c] = data[bounds[p][c]:bounds[p+1][c]]
+			size += len(runs[c])
+		}
+		inputs[p] = runs
+		outputs[p] = buffer[offset : offset+size]
+		offset += size
+	}
+	from := ops.trace.now()
+	runTasks(parts, func(p int) {
+		ops.trace.region(regionMerge, func() {
+			kWayMergeInto(outputs[p], inputs[p], ops.less)
+		})
+	})
+	// All chunks are merged at once, in a single level.
+	ops.trace.merged(1, from)
+
+	parallelCopy(data, buffer)
+}
+
+// kWaySplitters picks parts-1 ascending splitter values from evenly spaced samples of every chunk.
+func kWaySplitters[T any](data []T, chunks []chunk, parts int, less func(a, b T) bool) []T {
+	samples := make([]T, 0, parts*len(chunks))
+	for _, ch := range chunks {
+		size := ch.end - ch.start
+		for i := 0; i < parts; i++ {
+			samples = append(samples, data[ch.start+(i*size)/parts])
+		}
+	}
+	sort.Slice(samples, func(i, j int) bool {
+		return less(samples[i], samples[j])
+	})
+
+	splitters := make([]T, parts-1)
+	for i := range splitters {
+		splitters[i] = samples[((i+1)*len(samples))/parts]
+	}
+	return splitters
+}
+
+// kWayMergeInto merges the sorted runs into dst using a runHeap.
+func kWayMergeInto[T any](dst []T, runs [][]T, less func(a, b T) bool) {
+	h := newRunHeap(runs, less)
+	k := 0
+	for len(h.heap) > 1 {
+		dst[k], _ = h.pop()
+		k++
+	}
+	if len(h.heap) == 1 {
+		copy(dst[k:], h.runs[h.heap[0]])
+	}
+}
+
+// runHeap is a binary min-heap of run indexes ordered by the first value of every run.
+// Ties are resolved in favour of the lower run index, which keeps merges stable.
+type runHeap[T any] struct {
+	runs [][]T
+	heap []int
+	less func(a, b T) bool
+}
+
+func newRunHeap[T any](runs [][]T, less func(a, b T) bool) *runHeap[T] {
+	h := &runHeap[T]{runs: runs, heap: make([]int, 0, len(runs)), less: less}
+	for r := range runs {
+		if len(runs[r]) > 0 {
+			h.heap = append(h.heap, r)
+		}
+	}
+	for i := len(h.heap)/2 - 1; i >= 0; i-- {
+		h.down(i)
+	}
+	return h
+}
+
+func (x *runHeap[T]) before(i, j int) bool {
+	a, b := x.runs[i][0], x.runs[j][0]
+	if x.less(a, b) {
+		return true
+	}
+	if x.less(b, a) {
+		return false
+	}
+	return i < j
+}
+
+func (x *runHeap[T]) down(i int) {
+	for {
+		l := 2*i + 1
+		if l >= len(x.heap) {
+			return
+		}
+		m := l
+		if r := l + 1; r < len(x.heap) && x.before(x.heap[r], x.heap[l]) {
+			m = r
+		}
+		if !x.before(x.heap[m], x.heap[i]) {
+			return
+		}
+		x.heap[i], x.heap[m] = x.heap[m], x.heap[i]
+		i = m
+	}
+}
+
+// pop removes and returns the smallest first value, false when every run is exhausted.
+func (x *runHeap[T]) pop() (T, bool) {
+	if len(x.heap) == 0 {
+		var zero T
+		return zero, false
+	}
+	r := x.heap[0]
+	v := x.runs[r][0]
+	x.runs[r] = x.runs[r][1:]
+	if len(x.runs[r]) == 0 {
+		x.heap[0] = x.heap[len(x.heap)-1]
+		x.heap = x.heap[:len(x.heap)-1]
+	}
+	x.down(0)
+	return v, true
+}
@@ -0,0 +1,43 @@
+package parsort
+
+// Iterator returns the values of a slice in sorted order. The chunks of the slice are sorted
+// in parallel up front, the k-way merge of the chunks happens as values are requested,
+// so the cost of the merge is proportional to the number of values consumed.
+//
+// The slice is reordered chunk by chunk and must not be modified while the Iterator is in use.
+// An Iterator is not safe for concurrent use.
+type Iterator[T any] struct {
+	heap *runHeap[T]
+	left int
+}
+
+// Next returns the next value in order, or false once every value was returned.
+func (x *Iterator[T]) Next() (T, bool) {
+	v, ok := x.heap.pop()
+	if ok {
+		x.left--
+	}
+	return v, ok
+}
+
+// Len returns the number of values not yet returned by Next.
+func (x *Iterator[T]) Len() int {
+	return x.left
+}
+
+// lazySort sorts the chunks of data in parallel, one per core, and returns an Iterator merging
+// them on demand. Below threshold data is sorted sequentially as a single chunk.
+// A panic while sorting is raised again on the calling goroutine.
+func lazySort[T any](data []T, threshold int, ops *typeOps[T]) *Iterator[T] {
+	chunks := []chunk{{0, len(data)}}
+	if cores() > 1 && len(data) >= threshold {
+		chunks = splitChunks(len(data))
+	}
+	sortChunks(data, chunks, ops)
+
+	runs := make([][]T, len(chunks))
+	for i, c := range chunks {
+		runs[i] = data[c.start:c.end]
+	}
+	return &Iterator[T]{heap: newRunHeap(runs, ops.less), left: len(data)}
+}
@@ -0,0 +1,99 @@
+package parsort
+
+import (
+	"sort"
+	"testing"
+)
+
+func TestIntLazy(t *testing.T) {
+	withCoreCount(t, 4)
+	for _, n := range []int{0, 1, 100, 100000} {
+		data := genInts(n)
+		expected := append([]int(nil), data...)
+		sort.Ints(expected)
+
+		it := IntLazy(data)
+		if it.Len() != n {
+			t.Errorf("n=%d: expected Len %d, got %d", n, n, it.Len())
+		}
+		for i := 0; ; i++ {
+			v, ok := it.Next()
+			if !ok {
+				if i != n {
+					t.Errorf("n=%d: iterator stopped after %d values", n, i)
+				}
+				break
+			}
+			if v != expected[i] {
+				t.Fatalf("n=%d: value %d is %d, expected %d", n, i, v, expected[i])
+			}
+		}
+		if _, ok := it.Next(); ok || it.Len() != 0 {
+			t.Errorf("n=%d: exhausted iterator returned a value", n)
+		}
+	}
+}
+
+func TestIntLazy_ChunksSorted(t *testing.T) {
+	withCoreCount(t, 4)
+	data := genInts(100000)
+	it := IntLazy(data)
+	for i := 0; i < 10; i++ {
+		it.Next()
+	}
+	if it.Len() != len(data)-10 {
+		t.Errorf("unexpected Len %d", it.Len())
+	}
+	for _, c := range splitChunks(len(data)) {
+		if !sort.IntsAreSorted(data[c.start:c.end]) {
+			t.Errorf("chunk %v not sorted", c)
+		}
+	}
+}
+
+func TestStructLazy(t *testing.T) {
+	withCoreCount(t, 4)
+	data := genPeople(50000)
+	it := StructLazy(data, func(a, b person) bool { return a.Age < b.Age })
+	var got []person
+	for v, ok := it.Next(); ok; v, ok = it.Next() {
+		got = append(got, v)
+	}
+	if len(got) != len(data) || !isSortedAsc(got) {
+		t.Errorf("StructLazy result incorrect")
+	}
+}
+
+func TestStringLazy_First(t *testing.T) {
+	withCoreCount(t, 4)
+	data := genStrings(50000)
+	expected := append([]string(nil), data...)
+	sort.Strings(expected)
+	it := StringLazy(data)
+	for i := 0; i < 1000; i++ {
+		if v, _ := it.Next(); v != expected[i] {
+			t.Fatalf("value %d is %q, expected %q", i, v, expected[i])
+		}
+	}
+}
+
+func BenchmarkLazyFirst1000(b *testing.B) {
+	src := genInts(1000000)
+	data := make([]int, len(src))
+	b.Run("Full", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			copy(data, src)
+			IntAsc(data)
+			_ = data[:1000]
+		}
+	})
+	b.Run("Lazy", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			copy(data, src)
+			it := IntLazy(data)
+			for j := 0; j < 1000; j++ {
+				it.Next()
+			}
+		}
+	})
+}
@@ -0,0 +1,224 @@
+package parsort
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"unsafe"
+)
+
+// ErrMemoryBudget is returned by Sorter methods when no strategy fits in the Sorter's MaxExtraBytes.
+// The returned error wraps it with the budget and the smallest estimate.
+var ErrMemoryBudget = errors.New("parsort: no strategy fits in the memory budget")
+
+const (
+	// sortCallBytes bounds what one sequential sort call allocates: sort.Slice allocates its swapper,
+	// the struct functions their comparison closures.
+	sortCallBytes = 512
+	// pageBytes is the rounding applied by the runtime to every large allocation.
+	pageBytes = 8192
+	// chunkBytes is the bookkeeping allocated per chunk: bounds, merge tree node and results.
+	chunkBytes = 128
+	// workerBytes is the bookkeeping allocated per worker and per parallel phase.
+	workerBytes = 1024
+	// phases bounds the number of runTasks calls made by a strategy.
+	phases = 4
+	// autoSampleBytes is what Auto allocates to sample the input: the indexes of the sampled
+	// elements and the sort.Slice call sorting them.
+	autoSampleBytes = autoSampleSize*int64(unsafe.Sizeof(0)) + sortCallBytes
+)
+
+// Kind describes an element type to the functions that plan a sort without running it.
+type Kind struct {
+	name      string
+	threshold *int
+	shape     memShape
+}
+
+// memShape is what the memory estimates and plans need to know about an element type.
+type memShape struct {
+	size     int  // bytes per element
+	merge    bool // PairwiseMerge allocates every merge result
+	key      bool // Radix applies
+	keyBytes int  // significant key bytes, the number of Radix passes
+	structs  bool // sorted by the struct functions, indirectly when large
+}
+
+func (x Kind) String() string {
+	return x.name
+}
+
+func kindOf[T any](name string, threshold *int, ops *typeOps[T]) Kind {
+	return Kind{name: name, threshold: threshold, shape: shapeOf(ops)}
+}
+
+func shapeOf[T any](ops *typeOps[T]) memShape {
+	return memShape{
+		size:     int(unsafe.Sizeof(*new(T))),
+		merge:    ops.merge != nil,
+		key:      ops.key != nil,
+		keyBytes: ops.keyBytes,
+	}
+}
+
+// Kinds of the element types sorted by the package level functions.
+var (
+	KindInt     = kindOf("int", &IntMinParallelSize, &intOps)
+	KindInt8    = kindOf("int8", &Int8MinParallelSize, &int8Ops)
+	KindInt16   = kindOf("int16", &Int16MinParallelSize, &int16Ops)
+	KindInt32   = kindOf("int32", &Int32MinParallelSize, &int32Ops)
+	KindInt64   = kindOf("int64", &Int64MinParallelSize, &int64Ops)
+	KindUint    = kindOf("uint", &UintMinParallelSize, &uintOps)
+	KindUint8   = kindOf("uint8", &Uint8MinParallelSize, &uint8Ops)
+	KindUint16  = kindOf("uint16", &Uint16MinParallelSize, &uint16Ops)
+	KindUint32  = kindOf("uint32", &Uint32MinParallelSize, &uint32Ops)
+	KindUint64  = kindOf("uint64", &Uint64MinParallelSize, &uint64Ops)
+	KindFloat32 = kindOf("float32", &Float32MinParallelSize, &float32Ops)
+	KindFloat64 = kindOf("float64", &Float64MinParallelSize, &float64Ops)
+	KindString  = kindOf("string", &StringMinParallelSize, &stringOps)
+	KindTime    = kindOf("time.Time", &TimeMinParallelSize, &timeOps)
+)
+
+// KindStruct returns the Kind of T as sorted by StructAsc and the other struct functions.
+func KindStruct[T any]() Kind {
+	return Kind{
+		name:      "struct",
+		threshold: &StructMinParallelSize,
+		shape:     memShape{size: int(unsafe.Sizeof(*new(T))), structs: true},
+	}
+}
+
+// EstimateExtraBytes returns an upper bound on the bytes allocated by the package level functions
+// when sorting n elements of the given kind with the current configuration. For Auto, which
+// depends on the data, it is the largest estimate among the strategies Auto may choose plus
+// the sample Auto takes.
+func EstimateExtraBytes(kind Kind, n int) int64 {
+	b, _ := defaultSorter().EstimateExtraBytes(kind, n)
+	return b
+}
+
+// EstimateExtraBytes is the package level EstimateExtraBytes for the configuration of x,
+// including its memory budget. It returns the error the sort itself would return.
+func (x *Sorter) EstimateExtraBytes(kind Kind, n int) (int64, error) {
+	d := Decision{N: n, Threshold: *kind.threshold, Cores: cores()}
+	sh, index := x.planShape(kind, n)
+	budget := x.maxExtraBytes
+	if budget > 0 {
+		budget -= index
+	}
+
+	candidates := []Strategy{x.strategy}
+	var sample int64
+	switch {
+	case d.Cores <= 1 || x.strategy == Sequential:
+		candidates = []Strategy{Sequential}
+	case x.strategy == Auto:
+		candidates = []Strategy{Sequential}
+		if !autoSkip(&d, budget) {
+			candidates = append(candidates, PairwiseMerge, Radix, Samplesort, InPlace)
+			sample = autoSampleBytes
+			if budget > 0 {
+				budget -= sample
+			}
+		}
+	case n < d.Threshold:
+		candidates = []Strategy{Sequential}
+	}
+
+	var worst int64
+	for _, st := range candidates {
+		d.Strategy = st
+		if err := fitBudget(&d, sh, budget); err != nil {
+			return 0, err
+		}
+		if d.ExtraBytes > worst {
+			worst = d.ExtraBytes
+		}
+	}
+	return worst + index + sample, nil
+}
+
+// fitBudget fills in the chunk count and extra bytes of d. With a budget, it replaces d.Strategy
+// with the first of these that fits: the chosen strategy, PingPongMerge, InPlace, then the same
+// with fewer chunks, and finally Sequential.
+func fitBudget(d *Decision, sh memShape, budget int64) error {
+	chunks := chunkCount(d.Strategy, d.N)
+	d.Chunks = chunks
+	d.ExtraBytes = extraBytes(d.Strategy, d.N, chunks, sh)
+	if budget <= 0 || d.ExtraBytes <= budget {
+		return nil
+	}
+
+	if d.Strategy != Sequential {
+		candidates := []Strategy{d.Strategy, PingPongMerge, InPlace}
+		for k := taskCount(d.N); k >= 2; k /= 2 {
+			for _, st := range candidates {
+				c := chunkCount(st, d.N)
+				if c > k {
+					c = k
+				}
+				if b := extraBytes(st, d.N, c, sh); b <= budget {
+					d.Strategy, d.Chunks, d.ExtraBytes = st, c, b
+					d.Reason = "memory budget"
+					return nil
+				}
+			}
+		}
+	}
+
+	if b := extraBytes(Sequential, d.N, 1, sh); b <= budget {
+		d.Strategy, d.Chunks, d.ExtraBytes = Sequential, 1, b
+		d.Reason = "memory budget"
+		return nil
+	}
+	return fmt.Errorf("%w: %d bytes allowed, sorting %d elements needs at least %d", ErrMemoryBudget, budget, d.N, sortCallBytes)
+}
+
+// extraBytes estimates an upper bound on the bytes allocated when sorting n elements of the
+// given shape with strategy st split into chunks chunks.
+func extraBytes(st Strategy, n, chunks int, sh memShape) int64 {
+	if st == Sequential || n <= 1 || chunks <= 1 {
+		return sortCallBytes
+	}
+
+	if st == Radix && !sh.key {
+		st = PairwiseMerge
+	}
+
+	// Every buffer of n elements is one large allocation.
+	data := int64(n)*int64(sh.size) + pageBytes
+	k := int64(chunks)
+	blocks := int64(splitCount(n, cores()))
+	if st == Radix || st == Samplesort {
+		// chunks counts the blocks, Samplesort sorts TasksPerCore buckets per block.
+		blocks = k
+		if st == Samplesort {
+			k = int64(splitCount(n, tasksFor(chunks)))
+		}
+	}
+	workers := int64(cores())
+	base := k*(chunkBytes+sortCallBytes) + phases*workers*workerBytes
+
+	switch st {
+	case PairwiseMerge:
+		if sh.merge {
+			// Every merge allocates its result, each level of the tree allocates n elements.
+			return base + (data-pageBytes)*int64(bits.Len(uint(chunks-1))) + (k-1)*pageBytes
+		}
+		return base + data
+	case PingPongMerge, ChunkCopyMerge:
+		return base + data
+	case KWayMerge:
+		// Bounds, runs and samples per part and chunk, plus one heap per part.
+		perPart := 48 + k*(32+int64(sh.size)) + int64(sh.size)
+		return base + data + blocks*perPart
+	case Samplesort:
+		// Counts and offsets per block and bucket, plus the oversampled splitters.
+		return base + data + 2*blocks*(24+8*k) + k*(16+int64(sh.size)*(sampleOversampling+1))
+	case Radix:
+		return base + data + blocks*256*8
+	case InPlace:
+		return base
+	}
+	return base + data
+}
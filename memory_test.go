@@ -0,0 +1,175 @@
+package parsort
+
+import (
+	"errors"
+	"math/rand"
+	"runtime"
+	"sort"
+	"testing"
+)
+
+func allocatedBytes(fn func()) int64 {
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	fn()
+	runtime.ReadMemStats(&after)
+	return int64(after.TotalAlloc - before.TotalAlloc)
+}
+
+func TestEstimateExtraBytes_BoundsAllocations(t *testing.T) {
+	withCoreCount(t, 4)
+	const n = 200000
+	ints := genInts(n)
+	people := genPeople(n)
+	for _, st := range strategies {
+		s := NewSorter().SetStrategy(st)
+		est, err := s.EstimateExtraBytes(KindInt, n)
+		if err != nil {
+			t.Fatalf("%v: %v", st, err)
+		}
+		data := append([]int(nil), ints...)
+		got := allocatedBytes(func() { s.IntAsc(data) })
+		if got > est {
+			t.Errorf("%v ints: allocated %d bytes, estimated at most %d", st, got, est)
+		}
+		t.Logf("%v ints: allocated %d, estimated %d", st, got, est)
+
+		est, err = s.EstimateExtraBytes(KindStruct[person](), n)
+		if err != nil {
+			t.Fatalf("%v: %v", st, err)
+		}
+		p := append([]person(nil), people...)
+		less := func(a, b person) bool { return a.Age < b.Age }
+		got = allocatedBytes(func() { StructAscStableWith(s, p, less) })
+		if got > est {
+			t.Errorf("%v structs: allocated %d bytes, estimated at most %d", st, got, est)
+		}
+		t.Logf("%v structs: allocated %d, estimated %d", st, got, est)
+	}
+}
+
+// record68 is large enough for Auto to consider Samplesort, and small enough to be sorted directly.
+type record68 struct {
+	Key     int32
+	Payload [16]int32
+}
+
+func TestEstimateExtraBytes_Auto(t *testing.T) {
+	withCoreCount(t, 4)
+	less := func(a, b record68) bool { return a.Key < b.Key }
+	for _, n := range []int{20000, 200000} {
+		records := make([]record68, n)
+		for i := range records {
+			records[i].Key = rand.Int31()
+		}
+
+		s := NewSorter().SetStrategy(Auto)
+		est, err := s.EstimateExtraBytes(KindStruct[record68](), n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data := append([]record68(nil), records...)
+		if got := allocatedBytes(func() { StructAscWith(s, data, less) }); got > est {
+			t.Errorf("%d structs: allocated %d bytes, estimated at most %d", n, got, est)
+		}
+
+		budget := est / 2
+		s.SetMaxExtraBytes(budget)
+		data = append([]record68(nil), records...)
+		var serr error
+		got := allocatedBytes(func() { serr = StructAscWith(s, data, less) })
+		if serr != nil {
+			t.Fatalf("%d structs: %v", n, serr)
+		}
+		if d := s.LastDecision(); got > budget || d.ExtraBytes > budget || !d.Sampled {
+			t.Errorf("%d structs: allocated %d bytes with a budget of %d, decision %+v", n, got, budget, d)
+		}
+		if !sort.SliceIsSorted(data, func(i, j int) bool { return less(data[i], data[j]) }) {
+			t.Errorf("%d structs: slice not sorted", n)
+		}
+	}
+}
+
+func TestMaxExtraBytes_PicksStrategyWithinBudget(t *testing.T) {
+	withCoreCount(t, 4)
+	const n = 200000
+	cases := []struct {
+		budget int64
+		want   Strategy
+	}{
+		{0, PairwiseMerge},
+		{4 << 20, PingPongMerge},
+		{100 << 10, InPlace},
+		{20000, InPlace},
+		{1000, Sequential},
+	}
+	for _, c := range cases {
+		s := NewSorter().SetMaxExtraBytes(c.budget)
+		data := genInts(n)
+		if err := s.IntAsc(data); err != nil {
+			t.Fatalf("budget %d: %v", c.budget, err)
+		}
+		d := s.LastDecision()
+		if d.Strategy != c.want {
+			t.Errorf("budget %d: expected %v, got %+v", c.budget, c.want, d)
+		}
+		if c.budget > 0 && d.ExtraBytes > c.budget {
+			t.Errorf("budget %d: estimate %d is over budget", c.budget, d.ExtraBytes)
+		}
+		if !sort.IntsAreSorted(data) {
+			t.Errorf("budget %d: slice not sorted", c.budget)
+		}
+	}
+}
+
+func TestMaxExtraBytes_ReducesFanOut(t *testing.T) {
+	withCoreCount(t, 4)
+	s := NewSorter().SetStrategy(InPlace).SetMaxExtraBytes(20000)
+	data := genInts(200000)
+	if err := s.IntAsc(data); err != nil {
+		t.Fatal(err)
+	}
+	if d := s.LastDecision(); d.Strategy != InPlace || d.Chunks >= taskCount(len(data)) {
+		t.Errorf("expected InPlace with fewer chunks than %d, got %+v", taskCount(len(data)), d)
+	}
+	if !sort.IntsAreSorted(data) {
+		t.Errorf("slice not sorted")
+	}
+}
+
+func TestMaxExtraBytes_NothingFits(t *testing.T) {
+	withCoreCount(t, 4)
+	s := NewSorter().SetMaxExtraBytes(100)
+	data := genPeople(50000)
+	original := append([]person(nil), data...)
+
+	err := StructAscWith(s, data, func(a, b person) bool { return a.Age < b.Age })
+	if !errors.Is(err, ErrMemoryBudget) {
+		t.Fatalf("expected ErrMemoryBudget, got %v", err)
+	}
+	for i := range data {
+		if data[i] != original[i] {
+			t.Fatalf("data modified at %d despite the error", i)
+		}
+	}
+
+	if _, err := s.EstimateExtraBytes(KindStruct[person](), 50000); !errors.Is(err, ErrMemoryBudget) {
+		t.Errorf("expected EstimateExtraBytes to report ErrMemoryBudget, got %v", err)
+	}
+}
+
+func TestEstimateExtraBytes_Package(t *testing.T) {
+	withCoreCount(t, 4)
+	if b := EstimateExtraBytes(KindInt, 100); b != sortCallBytes {
+		t.Errorf("expected a sequential estimate below the threshold, got %d", b)
+	}
+	small := EstimateExtraBytes(KindInt32, 1000000)
+	large := EstimateExtraBytes(KindInt64, 1000000)
+	if small <= 0 || large <= small {
+		t.Errorf("expected the int64 estimate %d to exceed the int32 estimate %d", large, small)
+	}
+	if KindFloat64.String() != "float64" {
+		t.Errorf("unexpected kind name %q", KindFloat64)
+	}
+}
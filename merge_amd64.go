@@ -0,0 +1,64 @@
+//go:build amd64
+
+package parsort
+
+// hasAVX2 reports whether both the CPU and the operating system support AVX2.
+var hasAVX2 = detectAVX2()
+
+func cpuid(eaxArg, ecxArg uint32) (eax, ebx, ecx, edx uint32)
+
+func xgetbv() (eax, edx uint32)
+
+func detectAVX2() bool {
+	maxID, _, _, _ := cpuid(0, 0)
+	if maxID < 7 {
+		return false
+	}
+	_, _, ecx1, _ := cpuid(1, 0)
+	osxsave := ecx1&(1<<27) != 0
+	avx := ecx1&(1<<28) != 0
+	if !osxsave || !avx {
+		return false
+	}
+	// The OS must save the XMM and YMM registers on context switches.
+	if xcr0, _ := xgetbv(); xcr0&6 != 6 {
+		return false
+	}
+	_, ebx7, _, _ := cpuid(7, 0)
+	return ebx7&(1<<5) != 0
+}
+
+// The AVX2 kernels merge a and b into dst with bitonic merge networks while both inputs
+// can still fill a register, and report how much of a and b they consumed. The last
+// register of output is left unmerged at dst[ia+ib-lanes:], see mergeSIMD.
+// Both inputs must hold at least one register of elements, floats must not contain NaNs.
+
+func mergeIntAVX2(dst, a, b []int) (ia, ib int)
+
+func mergeInt32AVX2(dst, a, b []int32) (ia, ib int)
+
+func mergeInt64AVX2(dst, a, b []int64) (ia, ib int)
+
+func mergeUintAVX2(dst, a, b []uint) (ia, ib int)
+
+func mergeUint32AVX2(dst, a, b []uint32) (ia, ib int)
+
+func mergeUint64AVX2(dst, a, b []uint64) (ia, ib int)
+
+func mergeFloat32AVX2(dst, a, b []float32) (ia, ib int)
+
+func mergeFloat64AVX2(dst, a, b []float64) (ia, ib int)
+
+func init() {
+	if !hasAVX2 {
+		return
+	}
+	intMergeKernel = func(dst, a, b []int) { mergeSIMD(dst, a, b, 4, mergeIntAVX2) }
+	int32MergeKernel = func(dst, a, b []int32) { mergeSIMD(dst, a, b, 8, mergeInt32AVX2) }
+	int64MergeKernel = func(dst, a, b []int64) { mergeSIMD(dst, a, b, 4, mergeInt64AVX2) }
+	uintMergeKernel = func(dst, a, b []uint) { mergeSIMD(dst, a, b, 4, mergeUintAVX2) }
+	uint32MergeKernel = func(dst, a, b []uint32) { mergeSIMD(dst, a, b, 8, mergeUint32AVX2) }
+	uint64MergeKernel = func(dst, a, b []uint64) { mergeSIMD(dst, a, b, 4, mergeUint64AVX2) }
+	float32MergeKernel = func(dst, a, b []float32) { mergeSIMD(dst, a, b, 8, mergeFloat32AVX2) }
+	float64MergeKernel = func(dst, a, b []float64) { mergeSIMD(dst, a, b, 4, mergeFloat64AVX2) }
+}
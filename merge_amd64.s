@@ -0,0 +1,388 @@
+#include "textflag.h"
+
+// Permutation reversing the eight 32-bit lanes of a Y register.
+DATA rev32<>+0(SB)/4, $7
+DATA rev32<>+4(SB)/4, $6
+DATA rev32<>+8(SB)/4, $5
+DATA rev32<>+12(SB)/4, $4
+DATA rev32<>+16(SB)/4, $3
+DATA rev32<>+20(SB)/4, $2
+DATA rev32<>+24(SB)/4, $1
+DATA rev32<>+28(SB)/4, $0
+GLOBL rev32<>(SB), RODATA|NOPTR, $32
+
+// Sign bit of each 64-bit lane, used to compare unsigned lanes with a signed compare.
+DATA sign64<>+0(SB)/8, $0x8000000000000000
+DATA sign64<>+8(SB)/8, $0x8000000000000000
+DATA sign64<>+16(SB)/8, $0x8000000000000000
+DATA sign64<>+24(SB)/8, $0x8000000000000000
+GLOBL sign64<>(SB), RODATA|NOPTR, $32
+
+// BITONIC8 sorts the bitonic sequence of eight 32-bit lanes in R. Clobbers Y4-Y9.
+#define BITONIC8(R) \
+	VPERMQ   $0x4E, R, Y4;      \
+	MINMAX(R, Y4, Y5, Y6);      \
+	VPBLENDD $0xF0, Y6, Y5, R;  \
+	VPSHUFD  $0x4E, R, Y4;      \
+	MINMAX(R, Y4, Y5, Y6);      \
+	VPBLENDD $0xCC, Y6, Y5, R;  \
+	VPSHUFD  $0xB1, R, Y4;      \
+	MINMAX(R, Y4, Y5, Y6);      \
+	VPBLENDD $0xAA, Y6, Y5, R
+
+// BITONIC4 sorts the bitonic sequence of four 64-bit lanes in R. Clobbers Y4-Y9.
+#define BITONIC4(R) \
+	VPERMQ   $0x4E, R, Y4;      \
+	MINMAX(R, Y4, Y5, Y6);      \
+	VPBLENDD $0xF0, Y6, Y5, R;  \
+	VPSHUFD  $0x4E, R, Y4;      \
+	MINMAX(R, Y4, Y5, Y6);      \
+	VPBLENDD $0xCC, Y6, Y5, R
+
+// MERGE8 merges the sorted lanes of Y0 and Y1, leaving the lower half in Y2 and the upper half in Y0.
+#define MERGE8 \
+	VPERMD  Y1, Y15, Y1;   \
+	MINMAX(Y0, Y1, Y2, Y3); \
+	BITONIC8(Y2);          \
+	BITONIC8(Y3);          \
+	VMOVDQU Y3, Y0
+
+// MERGE4 merges the sorted lanes of Y0 and Y1, leaving the lower half in Y2 and the upper half in Y0.
+#define MERGE4 \
+	VPERMQ  $0x1B, Y1, Y1;  \
+	MINMAX(Y0, Y1, Y2, Y3); \
+	BITONIC4(Y2);           \
+	BITONIC4(Y3);           \
+	VMOVDQU Y3, Y0
+
+#define MINMAX(a, b, mn, mx) VPMINSD b, a, mn; VPMAXSD b, a, mx
+
+// func mergeInt32AVX2(dst, a, b []int32) (ia, ib int)
+TEXT ·mergeInt32AVX2(SB), NOSPLIT, $0-88
+	MOVQ dst_base+0(FP), DI
+	MOVQ a_base+24(FP), SI
+	MOVQ a_len+32(FP), R8
+	MOVQ b_base+48(FP), DX
+	MOVQ b_len+56(FP), R9
+	VMOVDQU rev32<>(SB), Y15
+	VMOVDQU (SI), Y0
+	VMOVDQU (DX), Y1
+	MOVQ    $8, R10
+	MOVQ    $8, R11
+
+loop:
+	MERGE8
+	VMOVDQU Y2, (DI)
+	ADDQ    $32, DI
+
+	// Stop once either input can't fill a register anymore.
+	LEAQ 8(R10), AX
+	CMPQ AX, R8
+	JGT  done
+	LEAQ 8(R11), AX
+	CMPQ AX, R9
+	JGT  done
+
+	// Continue with the input whose next element is smaller.
+	MOVL (SI)(R10*4), AX
+	CMPL AX, (DX)(R11*4)
+	JLE  takeA
+	VMOVDQU (DX)(R11*4), Y1
+	ADDQ    $8, R11
+	JMP     loop
+
+takeA:
+	VMOVDQU (SI)(R10*4), Y1
+	ADDQ    $8, R10
+	JMP     loop
+
+done:
+	// Hand the upper half back through dst, it still has to be merged with the tails.
+	VMOVDQU Y0, (DI)
+	VZEROUPPER
+	MOVQ    R10, ia+72(FP)
+	MOVQ    R11, ib+80(FP)
+	RET
+
+#undef MINMAX
+
+#define MINMAX(a, b, mn, mx) VPMINUD b, a, mn; VPMAXUD b, a, mx
+
+// func mergeUint32AVX2(dst, a, b []uint32) (ia, ib int)
+TEXT ·mergeUint32AVX2(SB), NOSPLIT, $0-88
+	MOVQ dst_base+0(FP), DI
+	MOVQ a_base+24(FP), SI
+	MOVQ a_len+32(FP), R8
+	MOVQ b_base+48(FP), DX
+	MOVQ b_len+56(FP), R9
+	VMOVDQU rev32<>(SB), Y15
+	VMOVDQU (SI), Y0
+	VMOVDQU (DX), Y1
+	MOVQ    $8, R10
+	MOVQ    $8, R11
+
+loop:
+	MERGE8
+	VMOVDQU Y2, (DI)
+	ADDQ    $32, DI
+
+	// Stop once either input can't fill a register anymore.
+	LEAQ 8(R10), AX
+	CMPQ AX, R8
+	JGT  done
+	LEAQ 8(R11), AX
+	CMPQ AX, R9
+	JGT  done
+
+	// Continue with the input whose next element is smaller.
+	MOVL (SI)(R10*4), AX
+	CMPL AX, (DX)(R11*4)
+	JLS  takeA
+	VMOVDQU (DX)(R11*4), Y1
+	ADDQ    $8, R11
+	JMP     loop
+
+takeA:
+	VMOVDQU (SI)(R10*4), Y1
+	ADDQ    $8, R10
+	JMP     loop
+
+done:
+	// Hand the upper half back through dst, it still has to be merged with the tails.
+	VMOVDQU Y0, (DI)
+	VZEROUPPER
+	MOVQ    R10, ia+72(FP)
+	MOVQ    R11, ib+80(FP)
+	RET
+
+#undef MINMAX
+
+// VMINPS and VMAXPS return their second operand for zeros of different sign, and a plain compare
+// ties them, so the networks would duplicate one of them. Equal lanes are ordered by their bits
+// as signed integers instead, which puts -0 before +0, and the lanes are blended on the result.
+#define MINMAX(a, b, mn, mx) \
+	VCMPPS    $1, a, b, Y7;  \
+	VCMPPS    $0, a, b, Y8;  \
+	VPCMPGTD  b, a, Y9;      \
+	VPAND     Y9, Y8, Y8;    \
+	VPOR      Y8, Y7, Y7;    \
+	VBLENDVPS Y7, b, a, mn;  \
+	VBLENDVPS Y7, a, b, mx
+
+// func mergeFloat32AVX2(dst, a, b []float32) (ia, ib int)
+TEXT ·mergeFloat32AVX2(SB), NOSPLIT, $0-88
+	MOVQ dst_base+0(FP), DI
+	MOVQ a_base+24(FP), SI
+	MOVQ a_len+32(FP), R8
+	MOVQ b_base+48(FP), DX
+	MOVQ b_len+56(FP), R9
+	VMOVDQU rev32<>(SB), Y15
+	VMOVDQU (SI), Y0
+	VMOVDQU (DX), Y1
+	MOVQ    $8, R10
+	MOVQ    $8, R11
+
+loop:
+	MERGE8
+	VMOVDQU Y2, (DI)
+	ADDQ    $32, DI
+
+	// Stop once either input can't fill a register anymore.
+	LEAQ 8(R10), AX
+	CMPQ AX, R8
+	JGT  done
+	LEAQ 8(R11), AX
+	CMPQ AX, R9
+	JGT  done
+
+	// Continue with the input whose next element is smaller.
+	VMOVSS   (SI)(R10*4), X4
+	VUCOMISS (DX)(R11*4), X4
+	JLS      takeA
+	VMOVDQU (DX)(R11*4), Y1
+	ADDQ    $8, R11
+	JMP     loop
+
+takeA:
+	VMOVDQU (SI)(R10*4), Y1
+	ADDQ    $8, R10
+	JMP     loop
+
+done:
+	// Hand the upper half back through dst, it still has to be merged with the tails.
+	VMOVDQU Y0, (DI)
+	VZEROUPPER
+	MOVQ    R10, ia+72(FP)
+	MOVQ    R11, ib+80(FP)
+	RET
+
+#undef MINMAX
+
+#define MINMAX(a, b, mn, mx) VPCMPGTQ b, a, Y7; VPBLENDVB Y7, b, a, mn; VPBLENDVB Y7, a, b, mx
+
+// func mergeInt64AVX2(dst, a, b []int64) (ia, ib int)
+TEXT ·mergeInt64AVX2(SB), NOSPLIT, $0-88
+	MOVQ dst_base+0(FP), DI
+	MOVQ a_base+24(FP), SI
+	MOVQ a_len+32(FP), R8
+	MOVQ b_base+48(FP), DX
+	MOVQ b_len+56(FP), R9
+
+	VMOVDQU (SI), Y0
+	VMOVDQU (DX), Y1
+	MOVQ    $4, R10
+	MOVQ    $4, R11
+
+loop:
+	MERGE4
+	VMOVDQU Y2, (DI)
+	ADDQ    $32, DI
+
+	// Stop once either input can't fill a register anymore.
+	LEAQ 4(R10), AX
+	CMPQ AX, R8
+	JGT  done
+	LEAQ 4(R11), AX
+	CMPQ AX, R9
+	JGT  done
+
+	// Continue with the input whose next element is smaller.
+	MOVQ (SI)(R10*8), AX
+	CMPQ AX, (DX)(R11*8)
+	JLE  takeA
+	VMOVDQU (DX)(R11*8), Y1
+	ADDQ    $4, R11
+	JMP     loop
+
+takeA:
+	VMOVDQU (SI)(R10*8), Y1
+	ADDQ    $4, R10
+	JMP     loop
+
+done:
+	// Hand the upper half back through dst, it still has to be merged with the tails.
+	VMOVDQU Y0, (DI)
+	VZEROUPPER
+	MOVQ    R10, ia+72(FP)
+	MOVQ    R11, ib+80(FP)
+	RET
+
+#undef MINMAX
+
+#define MINMAX(a, b, mn, mx) VPXOR a, Y14, Y8; VPXOR b, Y14, Y9; VPCMPGTQ Y9, Y8, Y7; VPBLENDVB Y7, b, a, mn; VPBLENDVB Y7, a, b, mx
+
+// func mergeUint64AVX2(dst, a, b []uint64) (ia, ib int)
+TEXT ·mergeUint64AVX2(SB), NOSPLIT, $0-88
+	MOVQ dst_base+0(FP), DI
+	MOVQ a_base+24(FP), SI
+	MOVQ a_len+32(FP), R8
+	MOVQ b_base+48(FP), DX
+	MOVQ b_len+56(FP), R9
+	VMOVDQU sign64<>(SB), Y14
+	VMOVDQU (SI), Y0
+	VMOVDQU (DX), Y1
+	MOVQ    $4, R10
+	MOVQ    $4, R11
+
+loop:
+	MERGE4
+	VMOVDQU Y2, (DI)
+	ADDQ    $32, DI
+
+	// Stop once either input can't fill a register anymore.
+	LEAQ 4(R10), AX
+	CMPQ AX, R8
+	JGT  done
+	LEAQ 4(R11), AX
+	CMPQ AX, R9
+	JGT  done
+
+	// Continue with the input whose next element is smaller.
+	MOVQ (SI)(R10*8), AX
+	CMPQ AX, (DX)(R11*8)
+	JLS  takeA
+	VMOVDQU (DX)(R11*8), Y1
+	ADDQ    $4, R11
+	JMP     loop
+
+takeA:
+	VMOVDQU (SI)(R10*8), Y1
+	ADDQ    $4, R10
+	JMP     loop
+
+done:
+	// Hand the upper half back through dst, it still has to be merged with the tails.
+	VMOVDQU Y0, (DI)
+	VZEROUPPER
+	MOVQ    R10, ia+72(FP)
+	MOVQ    R11, ib+80(FP)
+	RET
+
+#undef MINMAX
+
+// Ordered as the float32 lanes, see above.
+#define MINMAX(a, b, mn, mx) \
+	VCMPPD    $1, a, b, Y7;  \
+	VCMPPD    $0, a, b, Y8;  \
+	VPCMPGTQ  b, a, Y9;      \
+	VPAND     Y9, Y8, Y8;    \
+	VPOR      Y8, Y7, Y7;    \
+	VBLENDVPD Y7, b, a, mn;  \
+	VBLENDVPD Y7, a, b, mx
+
+// func mergeFloat64AVX2(dst, a, b []float64) (ia, ib int)
+TEXT ·mergeFloat64AVX2(SB), NOSPLIT, $0-88
+	MOVQ dst_base+0(FP), DI
+	MOVQ a_base+24(FP), SI
+	MOVQ a_len+32(FP), R8
+	MOVQ b_base+48(FP), DX
+	MOVQ b_len+56(FP), R9
+
+	VMOVDQU (SI), Y0
+	VMOVDQU (DX), Y1
+	MOVQ    $4, R10
+	MOVQ    $4, R11
+
+loop:
+	MERGE4
+	VMOVDQU Y2, (DI)
+	ADDQ    $32, DI
+
+	// Stop once either input can't fill a register anymore.
+	LEAQ 4(R10), AX
+	CMPQ AX, R8
+	JGT  done
+	LEAQ 4(R11), AX
+	CMPQ AX, R9
+	JGT  done
+
+	// Continue with the input whose next element is smaller.
+	VMOVSD   (SI)(R10*8), X4
+	VUCOMISD (DX)(R11*8), X4
+	JLS      takeA
+	VMOVDQU (DX)(R11*8), Y1
+	ADDQ    $4, R11
+	JMP     loop
+
+takeA:
+	VMOVDQU (SI)(R10*8), Y1
+	ADDQ    $4, R10
+	JMP     loop
+
+done:
+	// Hand the upper half back through dst, it still has to be merged with the tails.
+	VMOVDQU Y0, (DI)
+	VZEROUPPER
+	MOVQ    R10, ia+72(FP)
+	MOVQ    R11, ib+80(FP)
+	RET
+
+#undef MINMAX
+
+// func mergeIntAVX2(dst, a, b []int) (ia, ib int)
+TEXT ·mergeIntAVX2(SB), NOSPLIT, $0-88
+	JMP ·mergeInt64AVX2(SB)
+
+// func mergeUintAVX2(dst, a, b []uint) (ia, ib int)
+TEXT ·mergeUintAVX2(SB), NOSPLIT, $0-88
+	JMP ·mergeUint64AVX2(SB)
@@ -0,0 +1,22 @@
+//go:build amd64
+
+package parsort
+
+import (
+	"testing"
+)
+
+func TestMergeAVX2(t *testing.T) {
+	if !hasAVX2 {
+		t.Skip("AVX2 not supported")
+	}
+	checkMergeKernel(t, "int", genInts, func(dst, a, b []int) { mergeSIMD(dst, a, b, 4, mergeIntAVX2) })
+	checkMergeKernel(t, "int32", genInt32s, func(dst, a, b []int32) { mergeSIMD(dst, a, b, 8, mergeInt32AVX2) })
+	checkMergeKernel(t, "int32 duplicates", genSmallInt32s, func(dst, a, b []int32) { mergeSIMD(dst, a, b, 8, mergeInt32AVX2) })
+	checkMergeKernel(t, "int64", genExtremeInt64s, func(dst, a, b []int64) { mergeSIMD(dst, a, b, 4, mergeInt64AVX2) })
+	checkMergeKernel(t, "uint", genUints, func(dst, a, b []uint) { mergeSIMD(dst, a, b, 4, mergeUintAVX2) })
+	checkMergeKernel(t, "uint32", genExtremeUint32s, func(dst, a, b []uint32) { mergeSIMD(dst, a, b, 8, mergeUint32AVX2) })
+	checkMergeKernel(t, "uint64", genExtremeUint64s, func(dst, a, b []uint64) { mergeSIMD(dst, a, b, 4, mergeUint64AVX2) })
+	checkMergeKernel(t, "float32", genSignedFloat32s, func(dst, a, b []float32) { mergeSIMD(dst, a, b, 8, mergeFloat32AVX2) })
+	checkMergeKernel(t, "float64", genSignedFloats, func(dst, a, b []float64) { mergeSIMD(dst, a, b, 4, mergeFloat64AVX2) })
+}
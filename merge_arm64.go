@@ -0,0 +1,25 @@
+//go:build arm64
+
+package parsort
+
+// The NEON kernels merge a and b into dst with bitonic merge networks while both inputs
+// can still fill a register, and report how much of a and b they consumed. The last
+// register of output is left unmerged at dst[ia+ib-lanes:], see mergeSIMD.
+// Both inputs must hold at least one register of elements, floats must not contain NaNs.
+// They are only used when built with the parsort_neon tag, see merge_arm64_neon.go.
+
+func mergeIntNEON(dst, a, b []int) (ia, ib int)
+
+func mergeInt32NEON(dst, a, b []int32) (ia, ib int)
+
+func mergeInt64NEON(dst, a, b []int64) (ia, ib int)
+
+func mergeUintNEON(dst, a, b []uint) (ia, ib int)
+
+func mergeUint32NEON(dst, a, b []uint32) (ia, ib int)
+
+func mergeUint64NEON(dst, a, b []uint64) (ia, ib int)
+
+func mergeFloat32NEON(dst, a, b []float32) (ia, ib int)
+
+func mergeFloat64NEON(dst, a, b []float64) (ia, ib int)
@@ -0,0 +1,368 @@
+#include "textflag.h"
+
+// The networks keep each half of the sequence being merged in its own register and compare
+// them lane by lane, so every comparison is made once and its two results land in different
+// lanes. Between the steps, the lanes are shuffled so the next pairs line up.
+
+// MERGE4 merges the sorted lanes of V0 and V1, leaving the lower half in V2 and the upper half in V0.
+// Clobbers V1 and V3-V7.
+#define MERGE4 \
+	VREV64 V1.S4, V1.S4;                \
+	VEXT   $8, V1.B16, V1.B16, V1.B16;  \
+	MINMAX(V0, V1, V2, V3);             \
+	VZIP1  V3.D2, V2.D2, V4.D2;         \
+	VZIP2  V3.D2, V2.D2, V5.D2;         \
+	MINMAX(V4, V5, V6, V7);             \
+	VTRN1  V7.S4, V6.S4, V4.S4;         \
+	VTRN2  V7.S4, V6.S4, V5.S4;         \
+	MINMAX(V4, V5, V6, V7);             \
+	VZIP1  V7.S4, V6.S4, V2.S4;         \
+	VZIP2  V7.S4, V6.S4, V0.S4
+
+// MERGE2 merges the sorted lanes of V0 and V1, leaving the lower half in V2 and the upper half in V0.
+// Clobbers V1 and V3-V7.
+#define MERGE2 \
+	VEXT  $8, V1.B16, V1.B16, V1.B16;  \
+	MINMAX(V0, V1, V2, V3);            \
+	VZIP1 V3.D2, V2.D2, V4.D2;         \
+	VZIP2 V3.D2, V2.D2, V5.D2;         \
+	MINMAX(V4, V5, V6, V7);            \
+	VZIP1 V7.D2, V6.D2, V2.D2;         \
+	VZIP2 V7.D2, V6.D2, V0.D2
+
+#define MINMAX(a, b, mn, mx) VSMIN b.S4, a.S4, mn.S4; VSMAX b.S4, a.S4, mx.S4
+
+// func mergeInt32NEON(dst, a, b []int32) (ia, ib int)
+TEXT ·mergeInt32NEON(SB), NOSPLIT, $0-88
+	MOVD dst_base+0(FP), R0
+	MOVD a_base+24(FP), R1
+	MOVD a_len+32(FP), R2
+	MOVD b_base+48(FP), R3
+	MOVD b_len+56(FP), R4
+	VLD1 (R1), [V0.S4]
+	VLD1 (R3), [V1.S4]
+	MOVD $4, R5
+	MOVD $4, R6
+
+loop:
+	MERGE4
+	VST1.P [V2.S4], 16(R0)
+
+	// Stop once either input can't fill a register anymore.
+	ADD $4, R5, R7
+	CMP R2, R7
+	BGT done
+	ADD $4, R6, R7
+	CMP R4, R7
+	BGT done
+
+	// Continue with the input whose next element is smaller.
+	ADD  R5<<2, R1, R7
+	ADD  R6<<2, R3, R8
+	MOVW (R7), R9
+	MOVW (R8), R10
+	CMPW R10, R9
+	BLE  takeA
+	VLD1 (R8), [V1.S4]
+	ADD  $4, R6
+	B    loop
+
+takeA:
+	VLD1 (R7), [V1.S4]
+	ADD  $4, R5
+	B    loop
+
+done:
+	// Hand the upper half back through dst, it still has to be merged with the tails.
+	VST1 [V0.S4], (R0)
+	MOVD R5, ia+72(FP)
+	MOVD R6, ib+80(FP)
+	RET
+
+#undef MINMAX
+
+#define MINMAX(a, b, mn, mx) VUMIN b.S4, a.S4, mn.S4; VUMAX b.S4, a.S4, mx.S4
+
+// func mergeUint32NEON(dst, a, b []uint32) (ia, ib int)
+TEXT ·mergeUint32NEON(SB), NOSPLIT, $0-88
+	MOVD dst_base+0(FP), R0
+	MOVD a_base+24(FP), R1
+	MOVD a_len+32(FP), R2
+	MOVD b_base+48(FP), R3
+	MOVD b_len+56(FP), R4
+	VLD1 (R1), [V0.S4]
+	VLD1 (R3), [V1.S4]
+	MOVD $4, R5
+	MOVD $4, R6
+
+loop:
+	MERGE4
+	VST1.P [V2.S4], 16(R0)
+
+	// Stop once either input can't fill a register anymore.
+	ADD $4, R5, R7
+	CMP R2, R7
+	BGT done
+	ADD $4, R6, R7
+	CMP R4, R7
+	BGT done
+
+	// Continue with the input whose next element is smaller.
+	ADD  R5<<2, R1, R7
+	ADD  R6<<2, R3, R8
+	MOVWU(R7), R9
+	MOVWU(R8), R10
+	CMPW R10, R9
+	BLS  takeA
+	VLD1 (R8), [V1.S4]
+	ADD  $4, R6
+	B    loop
+
+takeA:
+	VLD1 (R7), [V1.S4]
+	ADD  $4, R5
+	B    loop
+
+done:
+	// Hand the upper half back through dst, it still has to be merged with the tails.
+	VST1 [V0.S4], (R0)
+	MOVD R5, ia+72(FP)
+	MOVD R6, ib+80(FP)
+	RET
+
+#undef MINMAX
+
+// Float lanes are selected with the mask of a single compare, VFMIN and VFMAX could both
+// return the same zero when -0 and +0 meet.
+#define MINMAX(a, b, mn, mx) \
+	VFCMGT b.S4, a.S4, V16.S4; \
+	VORR   V16.B16, V16.B16, mn.B16; \
+	VBSL   a.B16, b.B16, mn.B16;     \
+	VORR   V16.B16, V16.B16, mx.B16; \
+	VBSL   b.B16, a.B16, mx.B16
+
+// func mergeFloat32NEON(dst, a, b []float32) (ia, ib int)
+TEXT ·mergeFloat32NEON(SB), NOSPLIT, $0-88
+	MOVD dst_base+0(FP), R0
+	MOVD a_base+24(FP), R1
+	MOVD a_len+32(FP), R2
+	MOVD b_base+48(FP), R3
+	MOVD b_len+56(FP), R4
+	VLD1 (R1), [V0.S4]
+	VLD1 (R3), [V1.S4]
+	MOVD $4, R5
+	MOVD $4, R6
+
+loop:
+	MERGE4
+	VST1.P [V2.S4], 16(R0)
+
+	// Stop once either input can't fill a register anymore.
+	ADD $4, R5, R7
+	CMP R2, R7
+	BGT done
+	ADD $4, R6, R7
+	CMP R4, R7
+	BGT done
+
+	// Continue with the input whose next element is smaller.
+	ADD  R5<<2, R1, R7
+	ADD  R6<<2, R3, R8
+	FMOVS (R7), F28
+	FMOVS (R8), F29
+	FCMPS F29, F28
+	BLS   takeA
+	VLD1 (R8), [V1.S4]
+	ADD  $4, R6
+	B    loop
+
+takeA:
+	VLD1 (R7), [V1.S4]
+	ADD  $4, R5
+	B    loop
+
+done:
+	// Hand the upper half back through dst, it still has to be merged with the tails.
+	VST1 [V0.S4], (R0)
+	MOVD R5, ia+72(FP)
+	MOVD R6, ib+80(FP)
+	RET
+
+#undef MINMAX
+
+// There are no 64-bit VSMIN and VSMAX, lanes are selected with the mask of a compare.
+#define MINMAX(a, b, mn, mx) \
+	VCMGT  b.D2, a.D2, V16.D2; \
+	VORR   V16.B16, V16.B16, mn.B16; \
+	VBSL   a.B16, b.B16, mn.B16;     \
+	VORR   V16.B16, V16.B16, mx.B16; \
+	VBSL   b.B16, a.B16, mx.B16
+
+// func mergeInt64NEON(dst, a, b []int64) (ia, ib int)
+TEXT ·mergeInt64NEON(SB), NOSPLIT, $0-88
+	MOVD dst_base+0(FP), R0
+	MOVD a_base+24(FP), R1
+	MOVD a_len+32(FP), R2
+	MOVD b_base+48(FP), R3
+	MOVD b_len+56(FP), R4
+	VLD1 (R1), [V0.D2]
+	VLD1 (R3), [V1.D2]
+	MOVD $2, R5
+	MOVD $2, R6
+
+loop:
+	MERGE2
+	VST1.P [V2.D2], 16(R0)
+
+	// Stop once either input can't fill a register anymore.
+	ADD $2, R5, R7
+	CMP R2, R7
+	BGT done
+	ADD $2, R6, R7
+	CMP R4, R7
+	BGT done
+
+	// Continue with the input whose next element is smaller.
+	ADD  R5<<3, R1, R7
+	ADD  R6<<3, R3, R8
+	MOVD (R7), R9
+	MOVD (R8), R10
+	CMP  R10, R9
+	BLE  takeA
+	VLD1 (R8), [V1.D2]
+	ADD  $2, R6
+	B    loop
+
+takeA:
+	VLD1 (R7), [V1.D2]
+	ADD  $2, R5
+	B    loop
+
+done:
+	// Hand the upper half back through dst, it still has to be merged with the tails.
+	VST1 [V0.D2], (R0)
+	MOVD R5, ia+72(FP)
+	MOVD R6, ib+80(FP)
+	RET
+
+#undef MINMAX
+
+#define MINMAX(a, b, mn, mx) \
+	VCMHI  b.D2, a.D2, V16.D2; \
+	VORR   V16.B16, V16.B16, mn.B16; \
+	VBSL   a.B16, b.B16, mn.B16;     \
+	VORR   V16.B16, V16.B16, mx.B16; \
+	VBSL   b.B16, a.B16, mx.B16
+
+// func mergeUint64NEON(dst, a, b []uint64) (ia, ib int)
+TEXT ·mergeUint64NEON(SB), NOSPLIT, $0-88
+	MOVD dst_base+0(FP), R0
+	MOVD a_base+24(FP), R1
+	MOVD a_len+32(FP), R2
+	MOVD b_base+48(FP), R3
+	MOVD b_len+56(FP), R4
+	VLD1 (R1), [V0.D2]
+	VLD1 (R3), [V1.D2]
+	MOVD $2, R5
+	MOVD $2, R6
+
+loop:
+	MERGE2
+	VST1.P [V2.D2], 16(R0)
+
+	// Stop once either input can't fill a register anymore.
+	ADD $2, R5, R7
+	CMP R2, R7
+	BGT done
+	ADD $2, R6, R7
+	CMP R4, R7
+	BGT done
+
+	// Continue with the input whose next element is smaller.
+	ADD  R5<<3, R1, R7
+	ADD  R6<<3, R3, R8
+	MOVD (R7), R9
+	MOVD (R8), R10
+	CMP  R10, R9
+	BLS  takeA
+	VLD1 (R8), [V1.D2]
+	ADD  $2, R6
+	B    loop
+
+takeA:
+	VLD1 (R7), [V1.D2]
+	ADD  $2, R5
+	B    loop
+
+done:
+	// Hand the upper half back through dst, it still has to be merged with the tails.
+	VST1 [V0.D2], (R0)
+	MOVD R5, ia+72(FP)
+	MOVD R6, ib+80(FP)
+	RET
+
+#undef MINMAX
+
+#define MINMAX(a, b, mn, mx) \
+	VFCMGT b.D2, a.D2, V16.D2; \
+	VORR   V16.B16, V16.B16, mn.B16; \
+	VBSL   a.B16, b.B16, mn.B16;     \
+	VORR   V16.B16, V16.B16, mx.B16; \
+	VBSL   b.B16, a.B16, mx.B16
+
+// func mergeFloat64NEON(dst, a, b []float64) (ia, ib int)
+TEXT ·mergeFloat64NEON(SB), NOSPLIT, $0-88
+	MOVD dst_base+0(FP), R0
+	MOVD a_base+24(FP), R1
+	MOVD a_len+32(FP), R2
+	MOVD b_base+48(FP), R3
+	MOVD b_len+56(FP), R4
+	VLD1 (R1), [V0.D2]
+	VLD1 (R3), [V1.D2]
+	MOVD $2, R5
+	MOVD $2, R6
+
+loop:
+	MERGE2
+	VST1.P [V2.D2], 16(R0)
+
+	// Stop once either input can't fill a register anymore.
+	ADD $2, R5, R7
+	CMP R2, R7
+	BGT done
+	ADD $2, R6, R7
+	CMP R4, R7
+	BGT done
+
+	// Continue with the input whose next element is smaller.
+	ADD  R5<<3, R1, R7
+	ADD  R6<<3, R3, R8
+	FMOVD (R7), F28
+	FMOVD (R8), F29
+	FCMPD F29, F28
+	BLS   takeA
+	VLD1 (R8), [V1.D2]
+	ADD  $2, R6
+	B    loop
+
+takeA:
+	VLD1 (R7), [V1.D2]
+	ADD  $2, R5
+	B    loop
+
+done:
+	// Hand the upper half back through dst, it still has to be merged with the tails.
+	VST1 [V0.D2], (R0)
+	MOVD R5, ia+72(FP)
+	MOVD R6, ib+80(FP)
+	RET
+
+#undef MINMAX
+
+// func mergeIntNEON(dst, a, b []int) (ia, ib int)
+TEXT ·mergeIntNEON(SB), NOSPLIT, $0-88
+	JMP ·mergeInt64NEON(SB)
+
+// func mergeUintNEON(dst, a, b []uint) (ia, ib int)
+TEXT ·mergeUintNEON(SB), NOSPLIT, $0-88
+	JMP ·mergeUint64NEON(SB)
@@ -0,0 +1,17 @@
+//go:build arm64 && parsort_neon
+
+package parsort
+
+// The NEON kernels haven't been run on arm64 hardware yet, so mergeBranchless stays the
+// default and they are opt-in: go test -tags parsort_neon runs the whole suite with them.
+// NEON is part of every arm64 CPU, so no detection is needed once they are enabled.
+func init() {
+	intMergeKernel = func(dst, a, b []int) { mergeSIMD(dst, a, b, 2, mergeIntNEON) }
+	int32MergeKernel = func(dst, a, b []int32) { mergeSIMD(dst, a, b, 4, mergeInt32NEON) }
+	int64MergeKernel = func(dst, a, b []int64) { mergeSIMD(dst, a, b, 2, mergeInt64NEON) }
+	uintMergeKernel = func(dst, a, b []uint) { mergeSIMD(dst, a, b, 2, mergeUintNEON) }
+	uint32MergeKernel = func(dst, a, b []uint32) { mergeSIMD(dst, a, b, 4, mergeUint32NEON) }
+	uint64MergeKernel = func(dst, a, b []uint64) { mergeSIMD(dst, a, b, 2, mergeUint64NEON) }
+	float32MergeKernel = func(dst, a, b []float32) { mergeSIMD(dst, a, b, 4, mergeFloat32NEON) }
+	float64MergeKernel = func(dst, a, b []float64) { mergeSIMD(dst, a, b, 2, mergeFloat64NEON) }
+}
@@ -0,0 +1,19 @@
+//go:build arm64
+
+package parsort
+
+import (
+	"testing"
+)
+
+func TestMergeNEON(t *testing.T) {
+	checkMergeKernel(t, "int", genInts, func(dst, a, b []int) { mergeSIMD(dst, a, b, 2, mergeIntNEON) })
+	checkMergeKernel(t, "int32", genInt32s, func(dst, a, b []int32) { mergeSIMD(dst, a, b, 4, mergeInt32NEON) })
+	checkMergeKernel(t, "int32 duplicates", genSmallInt32s, func(dst, a, b []int32) { mergeSIMD(dst, a, b, 4, mergeInt32NEON) })
+	checkMergeKernel(t, "int64", genExtremeInt64s, func(dst, a, b []int64) { mergeSIMD(dst, a, b, 2, mergeInt64NEON) })
+	checkMergeKernel(t, "uint", genUints, func(dst, a, b []uint) { mergeSIMD(dst, a, b, 2, mergeUintNEON) })
+	checkMergeKernel(t, "uint32", genExtremeUint32s, func(dst, a, b []uint32) { mergeSIMD(dst, a, b, 4, mergeUint32NEON) })
+	checkMergeKernel(t, "uint64", genExtremeUint64s, func(dst, a, b []uint64) { mergeSIMD(dst, a, b, 2, mergeUint64NEON) })
+	checkMergeKernel(t, "float32", genSignedFloat32s, func(dst, a, b []float32) { mergeSIMD(dst, a, b, 4, mergeFloat32NEON) })
+	checkMergeKernel(t, "float64", genSignedFloats, func(dst, a, b []float64) { mergeSIMD(dst, a, b, 2, mergeFloat64NEON) })
+}
@@ -0,0 +1,68 @@
+package parsort
+
+// ordered matches the element types that can be compared with the < operator.
+type ordered interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64 | ~string
+}
+
+// Merge kernels of the 32 and 64-bit types. They default to mergeBranchless and are replaced
+// at init by SIMD kernels where the CPU supports them (see merge_amd64.go, and merge_arm64_neon.go
+// behind the parsort_neon build tag).
+var (
+	intMergeKernel     = mergeBranchless[int]
+	int32MergeKernel   = mergeBranchless[int32]
+	int64MergeKernel   = mergeBranchless[int64]
+	uintMergeKernel    = mergeBranchless[uint]
+	uint32MergeKernel  = mergeBranchless[uint32]
+	uint64MergeKernel  = mergeBranchless[uint64]
+	float32MergeKernel = mergeBranchless[float32]
+	float64MergeKernel = mergeBranchless[float64]
+)
+
+// mergeBranchless merges the sorted slices a and b into dst, which must have room for both.
+// The element to write is selected with a conditional move rather than a branch, which
+// avoids mispredictions on random input. NaNs are ordered before other values.
+func mergeBranchless[T ordered](dst, a, b []T) {
+	i, j, k := 0, 0, 0
+	for i < len(a) && j < len(b) {
+		x, y := a[i], b[j]
+		v, fromB := x, 0
+		if y < x || (y != y && x == x) {
+			v, fromB = y, 1
+		}
+		dst[k] = v
+		j += fromB
+		i += 1 - fromB
+		k++
+	}
+	k += copy(dst[k:], a[i:])
+	copy(dst[k:], b[j:])
+}
+
+// mergeSIMD merges a and b into dst using a SIMD kernel working on registers of lanes elements,
+// and finishes the tails with mergeBranchless. Inputs too short for the kernel, or starting
+// with a NaN (sorted float chunks hold their NaNs first), are merged by mergeBranchless only.
+func mergeSIMD[T ordered](dst, a, b []T, lanes int, kernel func(dst, a, b []T) (int, int)) {
+	if len(a) < lanes || len(b) < lanes || a[0] != a[0] || b[0] != b[0] {
+		mergeBranchless(dst, a, b)
+		return
+	}
+
+	ia, ib := kernel(dst, a, b)
+	k := ia + ib - lanes
+
+	// The kernel left its last register of output unmerged at dst[k:k+lanes]. At least one
+	// of the tails is shorter than a register, merge it with that register first.
+	var held [8]T
+	h := held[:copy(held[:lanes], dst[k:k+lanes])]
+	short, long := a[ia:], b[ib:]
+	if len(short) > len(long) {
+		short, long = long, short
+	}
+	var tmp [16]T
+	t := tmp[:len(h)+len(short)]
+	mergeBranchless(t, h, short)
+	mergeBranchless(dst[k:], t, long)
+}
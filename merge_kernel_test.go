@@ -0,0 +1,238 @@
+package parsort
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"testing"
+)
+
+// mergeScalar is the branchy merge loop the type specific merge functions used before the kernels.
+func mergeScalar[T ordered](a, b []T) []T {
+	res := make([]T, len(a)+len(b))
+	i, j, k := 0, 0, 0
+	for i < len(a) && j < len(b) {
+		if a[i] <= b[j] {
+			res[k] = a[i]
+			i++
+		} else {
+			res[k] = b[j]
+			j++
+		}
+		k++
+	}
+	for i < len(a) {
+		res[k] = a[i]
+		i++
+		k++
+	}
+	for j < len(b) {
+		res[k] = b[j]
+		j++
+		k++
+	}
+	return res
+}
+
+// checkMergeKernel merges sorted inputs of many length combinations with merge and compares the
+// result with mergeScalar. The result must also hold the bits of the inputs, so -0 and +0
+// can't stand in for each other.
+func checkMergeKernel[T ordered](t *testing.T, name string, gen func(n int) []T, merge func(dst, a, b []T)) {
+	t.Helper()
+	sizes := []int{0, 1, 3, 4, 5, 7, 8, 9, 15, 16, 17, 31, 33, 100, 1000, 4099}
+	for _, na := range sizes {
+		for _, nb := range sizes {
+			a, b := gen(na), gen(nb)
+			sort.Slice(a, func(i, j int) bool { return a[i] < a[j] })
+			sort.Slice(b, func(i, j int) bool { return b[i] < b[j] })
+			expected := mergeScalar(a, b)
+			res := make([]T, na+nb)
+			merge(res, a, b)
+			for i := range res {
+				if res[i] != expected[i] {
+					t.Errorf("%s: merge of %d and %d elements differs at %d: got %v, want %v", name, na, nb, i, res[i], expected[i])
+					break
+				}
+			}
+			counts := make(map[any]int)
+			for i := range res {
+				counts[valueBits(res[i])]++
+				counts[valueBits(expected[i])]--
+			}
+			for v, c := range counts {
+				if c != 0 {
+					t.Errorf("%s: merge of %d and %d elements holds %+d values with bits %v", name, na, nb, c, v)
+					break
+				}
+			}
+		}
+	}
+}
+
+// valueBits returns the bit pattern of floats, and v itself for the other types.
+func valueBits[T ordered](v T) any {
+	switch x := any(v).(type) {
+	case float32:
+		return math.Float32bits(x)
+	case float64:
+		return math.Float64bits(x)
+	}
+	return v
+}
+
+func genSmallInt32s(n int) []int32 {
+	a := make([]int32, n)
+	for i := range a {
+		a[i] = int32(rand.Intn(16) - 8)
+	}
+	return a
+}
+
+func genExtremeInt64s(n int) []int64 {
+	values := []int64{math.MinInt64, math.MinInt64 + 1, -1, 0, 1, math.MaxInt64 - 1, math.MaxInt64}
+	a := make([]int64, n)
+	for i := range a {
+		if i%2 == 0 {
+			a[i] = values[rand.Intn(len(values))]
+		} else {
+			a[i] = rand.Int63() - rand.Int63()
+		}
+	}
+	return a
+}
+
+func genExtremeUint64s(n int) []uint64 {
+	values := []uint64{0, 1, math.MaxInt64, math.MaxInt64 + 1, math.MaxUint64}
+	a := make([]uint64, n)
+	for i := range a {
+		if i%2 == 0 {
+			a[i] = values[rand.Intn(len(values))]
+		} else {
+			a[i] = rand.Uint64()
+		}
+	}
+	return a
+}
+
+func genExtremeUint32s(n int) []uint32 {
+	a := make([]uint32, n)
+	for i := range a {
+		a[i] = rand.Uint32() | uint32(i%2)<<31
+	}
+	return a
+}
+
+func genSignedFloats(n int) []float64 {
+	a := make([]float64, n)
+	for i := range a {
+		a[i] = (rand.Float64() - 0.5) * 1e6
+		if i%4 == 0 {
+			a[i] = math.Copysign(0, float64(rand.Intn(2)*2-1))
+		}
+	}
+	return a
+}
+
+func genSignedFloat32s(n int) []float32 {
+	a := make([]float32, n)
+	for i := range a {
+		a[i] = float32(rand.Intn(64)-32) / 4
+		if a[i] == 0 && rand.Intn(2) == 0 {
+			a[i] = float32(math.Copysign(0, -1))
+		}
+	}
+	return a
+}
+
+func TestMergeKernels(t *testing.T) {
+	checkMergeKernel(t, "int", genInts, intMergeKernel)
+	checkMergeKernel(t, "int32", genInt32s, int32MergeKernel)
+	checkMergeKernel(t, "int32 duplicates", genSmallInt32s, int32MergeKernel)
+	checkMergeKernel(t, "int64", genExtremeInt64s, int64MergeKernel)
+	checkMergeKernel(t, "uint", genUints, uintMergeKernel)
+	checkMergeKernel(t, "uint32", genExtremeUint32s, uint32MergeKernel)
+	checkMergeKernel(t, "uint64", genExtremeUint64s, uint64MergeKernel)
+	checkMergeKernel(t, "float32", genSignedFloat32s, float32MergeKernel)
+	checkMergeKernel(t, "float64", genSignedFloats, float64MergeKernel)
+}
+
+func TestMergeBranchless(t *testing.T) {
+	checkMergeKernel(t, "int32", genSmallInt32s, mergeBranchless[int32])
+	checkMergeKernel(t, "uint64", genExtremeUint64s, mergeBranchless[uint64])
+	checkMergeKernel(t, "float64", genSignedFloats, mergeBranchless[float64])
+	checkMergeKernel(t, "string", genStrings, mergeBranchless[string])
+}
+
+func TestMergeKernels_NaNFirst(t *testing.T) {
+	a := []float64{math.NaN(), 1, 2, 3, 4, 5, 6, 7, 8}
+	b := []float64{0, 1.5, 2.5, 3.5, 4.5, 5.5, 6.5, 7.5, 8.5}
+	res := make([]float64, len(a)+len(b))
+	float64MergeKernel(res, a, b)
+	if !math.IsNaN(res[0]) || !sort.Float64sAreSorted(res[1:]) {
+		t.Errorf("expected NaN first followed by ascending values, got %v", res)
+	}
+
+	float64MergeKernel(res, b, a)
+	if !math.IsNaN(res[0]) || !sort.Float64sAreSorted(res[1:]) {
+		t.Errorf("expected NaN first followed by ascending values, got %v", res)
+	}
+}
+
+func TestMergeKernels_SignedZeros(t *testing.T) {
+	negative := math.Copysign(0, -1)
+	a := []float64{negative, negative, negative, negative}
+	b := []float64{0, 0, 0, 0}
+	res := make([]float64, len(a)+len(b))
+	float64MergeKernel(res, a, b)
+	n := 0
+	for _, v := range res {
+		if v != 0 {
+			t.Fatalf("expected only zeros, got %v", res)
+		}
+		if math.Signbit(v) {
+			n++
+		}
+	}
+	if n != len(a) {
+		t.Errorf("expected %d negative zeros, got %d", len(a), n)
+	}
+
+	a32 := []float32{float32(negative), 0, 0, 0, 0, 0, 0, 0}
+	b32 := []float32{float32(negative), float32(negative), 0, 0, 0, 0, 0, 0}
+	res32 := make([]float32, len(a32)+len(b32))
+	float32MergeKernel(res32, a32, b32)
+	n = 0
+	for _, v := range res32 {
+		if math.Signbit(float64(v)) {
+			n++
+		}
+	}
+	if n != 3 {
+		t.Errorf("expected 3 negative zeros, got %d in %v", n, res32)
+	}
+}
+
+func BenchmarkMergeKernels(b *testing.B) {
+	for _, size := range []int{1000, 100000, 1000000} {
+		x, y := genInt32s(size), genInt32s(size)
+		sort.Slice(x, func(i, j int) bool { return x[i] < x[j] })
+		sort.Slice(y, func(i, j int) bool { return y[i] < y[j] })
+		dst := make([]int32, 2*size)
+		b.Run("Scalar_Int32_"+strconv.Itoa(size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				mergeScalar(x, y)
+			}
+		})
+		b.Run("Branchless_Int32_"+strconv.Itoa(size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				mergeBranchless(dst, x, y)
+			}
+		})
+		b.Run("Kernel_Int32_"+strconv.Itoa(size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				int32MergeKernel(dst, x, y)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package parsort
+
+import (
+	"expvar"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// metricsOn is set by PublishExpvar, the counters cost nothing until then.
+var metricsOn int32
+
+var (
+	metricsOnce sync.Once
+	metrics     struct {
+		calls       expvar.Map
+		sizes       expvar.Map
+		parallel    expvar.Int
+		sequential  expvar.Int
+		elements    expvar.Int
+		nanoseconds expvar.Int
+	}
+)
+
+// sizeBuckets are the upper bounds, relative to the MinParallelSize threshold of the element
+// type, of the buckets of the size histogram. Sizes of 16 times the threshold or more fall in
+// the last bucket.
+var sizeBuckets = []struct {
+	below float64
+	name  string
+}{
+	{0.25, "<0.25x"},
+	{0.5, "0.25x-0.5x"},
+	{1, "0.5x-1x"},
+	{2, "1x-2x"},
+	{4, "2x-4x"},
+	{16, "4x-16x"},
+}
+
+// PublishExpvar publishes counters of all sort calls as the expvar variable "parsort", served
+// at /debug/vars by the expvar handler:
+//
+//	calls       number of calls per element type
+//	parallel    calls that ran a parallel strategy
+//	sequential  calls that sorted on the calling goroutine
+//	elements    total number of elements sorted
+//	nanoseconds cumulative time spent sorting
+//	sizes       histogram of slice lengths relative to the MinParallelSize threshold of their type
+//
+// Counting starts with the first call, later calls have no effect.
+func PublishExpvar() {
+	metricsOnce.Do(func() {
+		root := new(expvar.Map)
+		metrics.calls.Init()
+		metrics.sizes.Init()
+		root.Set("calls", &metrics.calls)
+		root.Set("parallel", &metrics.parallel)
+		root.Set("sequential", &metrics.sequential)
+		root.Set("elements", &metrics.elements)
+		root.Set("nanoseconds", &metrics.nanoseconds)
+		root.Set("sizes", &metrics.sizes)
+		expvar.Publish("parsort", root)
+		atomic.StoreInt32(&metricsOn, 1)
+	})
+}
+
+// recordMetrics counts a finished sort call.
+func recordMetrics(typ string, d Decision, elapsed time.Duration) {
+	metrics.calls.Add(typ, 1)
+	if d.Strategy == Sequential {
+		metrics.sequential.Add(1)
+	} else {
+		metrics.parallel.Add(1)
+	}
+	metrics.elements.Add(int64(d.N))
+	metrics.nanoseconds.Add(int64(elapsed))
+	metrics.sizes.Add(sizeBucket(d.N, d.Threshold), 1)
+}
+
+// sizeBucket returns the name of the size histogram bucket of n elements for the given threshold.
+func sizeBucket(n, threshold int) string {
+	if threshold <= 0 {
+		threshold = 1
+	}
+	ratio := float64(n) / float64(threshold)
+	for _, b := range sizeBuckets {
+		if ratio < b.below {
+			return b.name
+		}
+	}
+	return ">=16x"
+}
@@ -0,0 +1,68 @@
+package parsort
+
+import (
+	"encoding/json"
+	"expvar"
+	"testing"
+)
+
+func TestPublishExpvar(t *testing.T) {
+	PublishExpvar()
+	PublishExpvar()
+
+	read := func() map[string]any {
+		var m map[string]any
+		if err := json.Unmarshal([]byte(expvar.Get("parsort").String()), &m); err != nil {
+			t.Fatal(err)
+		}
+		return m
+	}
+	count := func(m map[string]any, keys ...string) float64 {
+		var v any = m
+		for _, k := range keys {
+			mm, ok := v.(map[string]any)
+			if !ok {
+				return 0
+			}
+			v = mm[k]
+		}
+		f, _ := v.(float64)
+		return f
+	}
+
+	withCoreCount(t, 4)
+	before := read()
+	IntAsc(genInts(100))
+	IntAsc(genInts(5 * IntMinParallelSize))
+	StructAsc(genPeople(10), func(a, b person) bool { return a.Age < b.Age })
+	after := read()
+
+	checks := []struct {
+		keys []string
+		want float64
+	}{
+		{[]string{"calls", "int"}, 2},
+		{[]string{"calls", "parsort.person"}, 1},
+		{[]string{"sequential"}, 2},
+		{[]string{"parallel"}, 1},
+		{[]string{"elements"}, float64(110 + 5*IntMinParallelSize)},
+		{[]string{"sizes", "4x-16x"}, 1},
+	}
+	for _, c := range checks {
+		if got := count(after, c.keys...) - count(before, c.keys...); got != c.want {
+			t.Errorf("%v: expected +%v, got +%v", c.keys, c.want, got)
+		}
+	}
+	if count(after, "nanoseconds") <= count(before, "nanoseconds") {
+		t.Errorf("expected the cumulative time to grow")
+	}
+}
+
+func TestSizeBucket(t *testing.T) {
+	cases := map[int]string{0: "<0.25x", 2500: "0.25x-0.5x", 9999: "0.5x-1x", 10000: "1x-2x", 50000: "4x-16x", 160000: ">=16x"}
+	for n, want := range cases {
+		if got := sizeBucket(n, 10000); got != want {
+			t.Errorf("sizeBucket(%d) = %q, expected %q", n, got, want)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package parsort
+
+import (
+	"context"
+	"math"
+	"runtime/trace"
+	"strconv"
+	"sync/atomic"
+	"time"
+	"unsafe"
+)
+
+// Observer receives events describing the execution of every sort call made with a Sorter.
+// Observe is called on the goroutine that called the sort, once all workers are done with
+// the phase being reported. An Observer shared between Sorters or set as DefaultObserver
+// must be safe for concurrent use.
+type Observer interface {
+	Observe(e Event)
+}
+
+// ObserverFunc adapts a function to the Observer interface.
+type ObserverFunc func(e Event)
+
+// Observe calls x(e).
+func (x ObserverFunc) Observe(e Event) {
+	x(e)
+}
+
+// EventKind identifies an Event.
+type EventKind int
+
+const (
+	// EventDecision is sent once the path is decided, before sorting. Decision holds N, Threshold,
+	// the strategy and the number of chunks.
+	EventDecision EventKind = iota
+	// EventChunkSort reports the chunk sorting phase: Duration spans from the first chunk sort
+	// starting to the last one ending. Samplesort reports the sorting of its buckets.
+	EventChunkSort
+	// EventMergeLevel reports one merge level, Level 1 being the merges of sorted chunks. Merges start
+	// as soon as their inputs are ready, so Duration spans from the first merge of the level starting
+	// to the last one ending, and levels overlap each other and the chunk sorting phase.
+	EventMergeLevel
+	// EventDone is sent last. Duration is the total time of the call and Bytes the size of the
+	// buffers allocated by the strategy, bookkeeping excluded.
+	EventDone
+)
+
+func (x EventKind) String() string {
+	switch x {
+	case EventDecision:
+		return "Decision"
+	case EventChunkSort:
+		return "ChunkSort"
+	case EventMergeLevel:
+		return "MergeLevel"
+	case EventDone:
+		return "Done"
+	}
+	return "EventKind(" + strconv.Itoa(int(x)) + ")"
+}
+
+// Event is a step of a sort call reported to an Observer.
+type Event struct {
+	Kind EventKind
+	// Type is the element type of the slice.
+	Type string
+	// Decision is how the call is executed, it is the same for all events of a call.
+	Decision Decision
+	// Level is the merge level of EventMergeLevel, starting at 1.
+	Level int
+	// Duration is the duration of the phase, or of the whole call for EventDone.
+	Duration time.Duration
+	// Bytes is the size of the buffers allocated by the call, set for EventDone.
+	Bytes int64
+}
+
+// span is the time range covered by concurrent steps of a phase, in nanoseconds since the start of the sort.
+type span struct {
+	first, last int64
+}
+
+func newSpan() span {
+	return span{first: math.MaxInt64, last: -1}
+}
+
+func (x *span) record(from, to int64) {
+	for {
+		cur := atomic.LoadInt64(&x.first)
+		if from >= cur || atomic.CompareAndSwapInt64(&x.first, cur, from) {
+			break
+		}
+	}
+	for {
+		cur := atomic.LoadInt64(&x.last)
+		if to <= cur || atomic.CompareAndSwapInt64(&x.last, cur, to) {
+			break
+		}
+	}
+}
+
+func (x *span) duration() (time.Duration, bool) {
+	if x.last < 0 {
+		return 0, false
+	}
+	return time.Duration(x.last - x.first), true
+}
+
+// sortTrace collects the phases of one sort call for its Observer, and for the execution tracer
+// when it is on. It also stops the sort at the next phase once its context is done.
+// A nil *sortTrace records nothing.
+type sortTrace struct {
+	start     time.Time
+	chunkSort span
+	levels    []span
+	bytes     int64
+	// ctx holds the runtime/trace task of the sort, nil when the execution tracer is off.
+	ctx context.Context
+	// done is the Done channel of the Sorter's context, nil when the sort can't be cancelled.
+	done <-chan struct{}
+}
+
+func newSortTrace(start time.Time, chunks int) *sortTrace {
+	x := &sortTrace{
+		start:     start,
+		chunkSort: newSpan(),
+		levels:    make([]span, mergeTreeDepth(chunks)),
+	}
+	for i := range x.levels {
+		x.levels[i] = newSpan()
+	}
+	return x
+}
+
+// now returns the time elapsed since the start of the sort, 0 for a nil trace.
+func (x *sortTrace) now() int64 {
+	if x == nil {
+		return 0
+	}
+	return int64(time.Since(x.start))
+}
+
+// Names of the runtime/trace regions of the sort phases.
+const (
+	regionChunkSort = "parsort.chunkSort"
+	regionMerge     = "parsort.merge"
+	regionPartition = "parsort.partition"
+	regionRadixPass = "parsort.radixPass"
+)
+
+// region runs fn inside a runtime/trace region of the sort's task when the execution tracer is on.
+// It panics with errCanceled instead when the sort's context is done.
+func (x *sortTrace) region(name string, fn func()) {
+	if x != nil && x.done != nil {
+		select {
+		case <-x.done:
+			panic(errCanceled)
+		default:
+		}
+	}
+	if x == nil || x.ctx == nil {
+		fn()
+		return
+	}
+	trace.WithRegion(x.ctx, name, fn)
+}
+
+func (x *sortTrace) chunkSorted(from int64) {
+	if x != nil {
+		x.chunkSort.record(from, x.now())
+	}
+}
+
+func (x *sortTrace) merged(level int, from int64) {
+	if x != nil && level >= 1 && level <= len(x.levels) {
+		x.levels[level-1].record(from, x.now())
+	}
+}
+
+func (x *sortTrace) alloc(bytes int64) {
+	if x != nil {
+		atomic.AddInt64(&x.bytes, bytes)
+	}
+}
+
+// emit sends the phases recorded by x, then EventDone.
+func (x *sortTrace) emit(o Observer, e Event) {
+	if d, ok := x.chunkSort.duration(); ok {
+		e.Kind, e.Duration = EventChunkSort, d
+		o.Observe(e)
+	}
+	for i := range x.levels {
+		if d, ok := x.levels[i].duration(); ok {
+			e.Kind, e.Level, e.Duration = EventMergeLevel, i+1, d
+			o.Observe(e)
+		}
+	}
+	e.Kind, e.Level, e.Duration, e.Bytes = EventDone, 0, time.Since(x.start), atomic.LoadInt64(&x.bytes)
+	o.Observe(e)
+}
+
+// buffer allocates a buffer of n elements and records its size on the trace.
+func (x *typeOps[T]) buffer(n int) []T {
+	x.trace.alloc(int64(n) * int64(unsafe.Sizeof(*new(T))))
+	return make([]T, n)
+}
@@ -0,0 +1,102 @@
+package parsort
+
+import (
+	"sync"
+	"testing"
+)
+
+type recorder struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+func (x *recorder) Observe(e Event) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.events = append(x.events, e)
+}
+
+func (x *recorder) kinds() map[EventKind]int {
+	m := map[EventKind]int{}
+	for _, e := range x.events {
+		m[e.Kind]++
+	}
+	return m
+}
+
+func TestObserver_Sequential(t *testing.T) {
+	rec := &recorder{}
+	s := NewSorter().SetObserver(rec)
+	s.IntAsc(genInts(100))
+
+	if len(rec.events) != 2 || rec.events[0].Kind != EventDecision || rec.events[1].Kind != EventDone {
+		t.Fatalf("expected a decision and a done event, got %+v", rec.events)
+	}
+	d := rec.events[0].Decision
+	if d.Strategy != Sequential || d.N != 100 || d.Threshold != IntMinParallelSize || rec.events[0].Type != "int" {
+		t.Errorf("unexpected decision event %+v", rec.events[0])
+	}
+	if rec.events[1].Duration < 0 || rec.events[1].Bytes != 0 {
+		t.Errorf("unexpected done event %+v", rec.events[1])
+	}
+}
+
+func TestObserver_Phases(t *testing.T) {
+	withCoreCount(t, 4)
+	const n = 200000
+	cases := []struct {
+		strategy Strategy
+		levels   int
+		chunks   int
+		bytes    int64
+	}{
+		{PairwiseMerge, 4, 16, 4 * n * 8},
+		{PingPongMerge, 4, 16, n * 8},
+		{InPlace, 4, 16, 0},
+		{KWayMerge, 1, 16, n * 8},
+		{Samplesort, 0, 4, n * 8},
+		{Radix, 0, 4, n * 8},
+	}
+	for _, c := range cases {
+		rec := &recorder{}
+		s := NewSorter().SetStrategy(c.strategy).SetObserver(rec)
+		s.IntAsc(genInts(n))
+
+		k := rec.kinds()
+		if k[EventDecision] != 1 || k[EventDone] != 1 || k[EventMergeLevel] != c.levels {
+			t.Errorf("%v: unexpected events %v", c.strategy, k)
+		}
+		if c.strategy != Radix && k[EventChunkSort] != 1 {
+			t.Errorf("%v: expected a chunk sort event, got %v", c.strategy, k)
+		}
+		if d := rec.events[0].Decision; d.Chunks != c.chunks {
+			t.Errorf("%v: expected %d chunks, got %d", c.strategy, c.chunks, d.Chunks)
+		}
+
+		level := 0
+		for _, e := range rec.events {
+			if e.Kind == EventMergeLevel {
+				level++
+				if e.Level != level || e.Duration <= 0 {
+					t.Errorf("%v: unexpected merge level event %+v", c.strategy, e)
+				}
+			}
+		}
+		done := rec.events[len(rec.events)-1]
+		if done.Kind != EventDone || done.Bytes != c.bytes {
+			t.Errorf("%v: expected %d bytes in the last event, got %+v", c.strategy, c.bytes, done)
+		}
+	}
+}
+
+func TestObserver_DefaultObserver(t *testing.T) {
+	rec := &recorder{}
+	prev := DefaultObserver
+	DefaultObserver = ObserverFunc(rec.Observe)
+	defer func() { DefaultObserver = prev }()
+
+	StructAsc(genPeople(1000), func(a, b person) bool { return a.Age < b.Age })
+	if len(rec.events) != 2 || rec.events[0].Type != "parsort.person" {
+		t.Errorf("expected the package functions to report to DefaultObserver, got %+v", rec.events)
+	}
+}
@@ -0,0 +1,38 @@
+package parsort
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// PanicError is a panic raised by a comparison function, or anything else called while sorting,
+// in one of the goroutines of a parallel sort. The remaining work is stopped and the panic is
+// raised again on the calling goroutine with a *PanicError value, or returned by the Sorter
+// methods. The contents of the slice are unspecified after a panic.
+type PanicError struct {
+	// Value is the value passed to panic.
+	Value any
+	// Stack is the stack of the goroutine that panicked, as returned by debug.Stack.
+	Stack []byte
+}
+
+func (x *PanicError) Error() string {
+	return fmt.Sprintf("parsort: panic while sorting: %v\n\n%s", x.Value, x.Stack)
+}
+
+// Unwrap returns Value when it is an error.
+func (x *PanicError) Unwrap() error {
+	if err, ok := x.Value.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// newPanicError wraps a recovered value with the current stack, it must be called by the
+// deferred function that recovered it. A *PanicError raised by a nested sort is kept as is.
+func newPanicError(r any) *PanicError {
+	if p, ok := r.(*PanicError); ok {
+		return p
+	}
+	return &PanicError{Value: r, Stack: debug.Stack()}
+}
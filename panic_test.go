@@ -0,0 +1,148 @@
+package parsort
+
+import (
+	"errors"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type badRecord struct {
+	Age *int
+}
+
+func genBadRecords(n, nilAt int) []badRecord {
+	people := genPeople(n)
+	data := make([]badRecord, n)
+	for i := range data {
+		if i != nilAt {
+			age := people[i].Age
+			data[i].Age = &age
+		}
+	}
+	return data
+}
+
+func badLess(a, b badRecord) bool {
+	return *a.Age < *b.Age
+}
+
+func TestPanic_RaisedOnCaller(t *testing.T) {
+	withCoreCount(t, 4)
+	for _, st := range strategies {
+		prev := DefaultStrategy
+		DefaultStrategy = st
+		data := genBadRecords(100000, 77777)
+
+		var recovered any
+		func() {
+			defer func() {
+				recovered = recover()
+			}()
+			StructAscStable(data, badLess)
+		}()
+		DefaultStrategy = prev
+
+		if recovered == nil {
+			t.Errorf("%v: expected a panic", st)
+			continue
+		}
+		// The sequential path panics on the caller with the original value.
+		if p, ok := recovered.(*PanicError); ok {
+			if _, ok := p.Value.(runtime.Error); !ok {
+				t.Errorf("%v: expected the runtime error as panic value, got %v", st, p.Value)
+			}
+			if !strings.Contains(string(p.Stack), "badLess") {
+				t.Errorf("%v: expected the stack of the panicking goroutine, got %s", st, p.Stack)
+			}
+		} else if _, ok := recovered.(runtime.Error); !ok {
+			t.Errorf("%v: unexpected panic value %v", st, recovered)
+		}
+	}
+	if b := atomic.LoadInt64(&busyWorkers); b != 0 {
+		t.Errorf("expected every worker to be released, %d still busy", b)
+	}
+}
+
+func TestPanic_ReturnedBySorter(t *testing.T) {
+	withCoreCount(t, 4)
+	s := NewSorter().SetStrategy(InPlace)
+	data := genBadRecords(100000, 12345)
+
+	err := StructAscWith(s, data, badLess)
+	var p *PanicError
+	if !errors.As(err, &p) {
+		t.Fatalf("expected a *PanicError, got %v", err)
+	}
+	var re runtime.Error
+	if !errors.As(err, &re) {
+		t.Errorf("expected the error to unwrap to the runtime error, got %v", p.Value)
+	}
+}
+
+func TestPanic_ReturnedByZeroSorter(t *testing.T) {
+	withCoreCount(t, 4)
+	var s Sorter
+	err := StructAscWith(&s, genBadRecords(100000, 12345), badLess)
+	var p *PanicError
+	if !errors.As(err, &p) {
+		t.Fatalf("expected a *PanicError, got %v", err)
+	}
+
+	data := genPriced(50000)
+	data[31337].Price = "12.5x"
+	err = StructAscErrWith(&s, data, comparePrices)
+	var numErr *strconv.NumError
+	if !errors.As(err, &numErr) {
+		t.Errorf("expected the comparison error, got %v", err)
+	}
+
+	s.SetCheckLess(true)
+	err = StructAscWith(&s, genPeople(1000), func(a, b person) bool { return a.Age <= b.Age })
+	var c *ComparatorError
+	if !errors.As(err, &c) {
+		t.Errorf("expected a *ComparatorError, got %v", err)
+	}
+}
+
+func TestPanic_StopsRemainingTasks(t *testing.T) {
+	withCoreCount(t, 4)
+	var started int32
+	func() {
+		defer func() {
+			if _, ok := recover().(*PanicError); !ok {
+				t.Errorf("expected a *PanicError")
+			}
+		}()
+		runTasks(1000, func(i int) {
+			atomic.AddInt32(&started, 1)
+			if i == 0 {
+				panic("boom")
+			}
+			time.Sleep(100 * time.Microsecond)
+		})
+	}()
+	if n := atomic.LoadInt32(&started); n >= 1000 {
+		t.Errorf("expected the panic to stop the remaining tasks, %d started", n)
+	}
+}
+
+func TestPanic_NestedKeepsOriginal(t *testing.T) {
+	withCoreCount(t, 4)
+	defer func() {
+		p, ok := recover().(*PanicError)
+		if !ok || p.Value != "inner" {
+			t.Errorf("expected the inner panic, got %v", p)
+		}
+	}()
+	runTasks(4, func(i int) {
+		runTasks(4, func(j int) {
+			if i == 2 && j == 3 {
+				panic("inner")
+			}
+		})
+	})
+}
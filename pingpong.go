@@ -0,0 +1,22 @@
+package parsort
+
+// pingPongMergeSort sorts the chunks and merges them pairwise, alternating between data and
+// a single buffer of the same length. The root of the merge tree is written to data, so chunks
+// whose depth puts them in the buffer are copied there before being sorted. When ops.src is
+// set, every chunk is copied out of it.
+func pingPongMergeSort[T any](data []T, ops *typeOps[T]) {
+	chunks := ops.split(len(data))
+	buffer := ops.buffer(len(data))
+	in := data
+	if ops.src != nil {
+		in = ops.src
+	}
+
+	pingPongTree(data, buffer, chunks, ops, func(i int, dst []T) {
+		c := dst[chunks[i].start:chunks[i].end]
+		if &dst[0] != &in[0] {
+			copy(c, in[chunks[i].start:chunks[i].end])
+		}
+		ops.sort(c)
+	})
+}
@@ -0,0 +1,142 @@
+package parsort
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SortPlan describes how a sort of N elements of a Kind would run, without running it.
+// It is computed with the same decision logic as the sort functions.
+type SortPlan struct {
+	// Kind is the element type being planned for.
+	Kind Kind
+	// Strategy is the strategy that would run, Sequential when the slice is sorted on the calling goroutine.
+	Strategy Strategy
+	// N is the number of elements.
+	N int
+	// Threshold is the MinParallelSize value of the element type.
+	Threshold int
+	// Cores is the number of cores the sort could use.
+	Cores int
+	// Chunks is the number of chunks sorted in parallel, 1 for Sequential, or the number of
+	// blocks partitioned in parallel for Radix and Samplesort.
+	Chunks int
+	// MergeLevels is the number of merge levels after the chunks are sorted: the height of the
+	// merge tree, 1 for KWayMerge and 0 for the strategies that don't merge.
+	MergeLevels int
+	// RadixPasses is the number of passes Radix makes over the data, 0 for the other strategies.
+	RadixPasses int
+	// Indirect is set when the structs would be sorted through an array of their indexes.
+	Indirect bool
+	// ExtraBytes is an upper bound on the bytes allocated by the sort, and so on its peak extra memory.
+	ExtraBytes int64
+	// DataDependent is set when the strategy is Auto: the choice made at sort time depends on the
+	// data and the plan assumes unordered input without duplicates.
+	DataDependent bool
+	// Reason is a short human readable explanation of the choice.
+	Reason string
+}
+
+// Plan returns how the package level functions would sort n elements of the given kind
+// with the current configuration.
+func Plan(kind Kind, n int) SortPlan {
+	p, _ := defaultSorter().Explain(kind, n)
+	return p
+}
+
+// Explain returns how x would sort n elements of the given kind. It returns the error
+// the sort itself would return when nothing fits in the memory budget of x.
+func (x *Sorter) Explain(kind Kind, n int) (SortPlan, error) {
+	d := Decision{N: n, Threshold: *kind.threshold, Cores: cores()}
+	sh, index := x.planShape(kind, n)
+	budget := x.maxExtraBytes
+	if budget > 0 {
+		budget -= index
+	}
+	if choose(&d, x.strategy, sh.key) {
+		autoAssume(&d, sh, budget)
+	}
+	if d.Sampled && budget > 0 {
+		budget -= autoSampleBytes
+	}
+	err := fitBudget(&d, sh, budget)
+	if d.Sampled {
+		d.ExtraBytes += autoSampleBytes
+	}
+
+	p := SortPlan{
+		Kind:          kind,
+		Strategy:      d.Strategy,
+		N:             d.N,
+		Threshold:     d.Threshold,
+		Cores:         d.Cores,
+		Chunks:        d.Chunks,
+		ExtraBytes:    d.ExtraBytes + index,
+		Indirect:      index > 0,
+		DataDependent: x.strategy == Auto,
+		Reason:        d.Reason,
+	}
+	switch d.Strategy {
+	case PairwiseMerge, PingPongMerge, ChunkCopyMerge, InPlace:
+		p.MergeLevels = mergeTreeDepth(d.Chunks)
+	case KWayMerge:
+		p.MergeLevels = 1
+	case Radix:
+		p.RadixPasses = sh.keyBytes
+	}
+	return p, err
+}
+
+// autoAssume fills in the strategy Auto would choose for unordered input without duplicates
+// whose keys use every significant byte, under the memory budget left for the sort.
+func autoAssume(d *Decision, sh memShape, budget int64) {
+	d.ElemSize = sh.size
+	if autoSkip(d, budget) {
+		return
+	}
+	d.Sampled = true
+	sample := *d
+	sample.Sortedness = 0.5
+	if sh.key {
+		sample.KeyBits = 8 * sh.keyBytes
+	}
+	autoChoose(&sample, sh.key, false)
+	d.Strategy, d.Reason = sample.Strategy, sample.Reason
+}
+
+// String returns a one line summary of the plan, for debugging.
+func (x SortPlan) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d %s (threshold %d, %d cores): %s", x.N, x.Kind, x.Threshold, x.Cores, x.Strategy)
+	if x.Indirect {
+		b.WriteString(" on indexes")
+	}
+	if x.DataDependent {
+		b.WriteString(" (Auto, assuming unordered distinct input)")
+	}
+	if x.Chunks > 1 {
+		fmt.Fprintf(&b, ", %d chunks", x.Chunks)
+	}
+	if x.MergeLevels > 0 {
+		fmt.Fprintf(&b, ", %d merge levels", x.MergeLevels)
+	}
+	if x.RadixPasses > 0 {
+		fmt.Fprintf(&b, ", %d radix passes", x.RadixPasses)
+	}
+	fmt.Fprintf(&b, ", up to %s extra memory: %s", formatBytes(x.ExtraBytes), x.Reason)
+	return b.String()
+}
+
+// formatBytes formats b with a binary unit.
+func formatBytes(b int64) string {
+	const unit = 1024
+	if b < unit {
+		return fmt.Sprintf("%d B", b)
+	}
+	div, exp := int64(unit), 0
+	for n := b / unit; n >= unit && exp < 3; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(b)/float64(div), "KMGT"[exp])
+}
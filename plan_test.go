@@ -0,0 +1,97 @@
+package parsort
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestExplain_MatchesDecision(t *testing.T) {
+	withCoreCount(t, 4)
+	for _, st := range []Strategy{Sequential, PairwiseMerge, PingPongMerge, KWayMerge, Samplesort, Radix, InPlace, Auto} {
+		s := NewSorter().SetStrategy(st)
+
+		p, err := s.Explain(KindInt, 300000)
+		if err != nil {
+			t.Fatalf("%s: %v", st, err)
+		}
+		s.IntAsc(genInts(300000))
+		d := s.LastDecision()
+		if p.Strategy != d.Strategy || p.Chunks != d.Chunks || p.ExtraBytes != d.ExtraBytes {
+			t.Errorf("%s: plan %+v does not match decision %+v", st, p, d)
+		}
+
+		p, _ = s.Explain(KindStruct[person](), 50000)
+		StructAscWith(s, genPeople(50000), func(a, b person) bool { return a.Age < b.Age })
+		d = s.LastDecision()
+		if p.Strategy != d.Strategy || p.Chunks != d.Chunks || p.ExtraBytes != d.ExtraBytes {
+			t.Errorf("%s: struct plan %+v does not match decision %+v", st, p, d)
+		}
+	}
+}
+
+func TestExplain_Levels(t *testing.T) {
+	withCoreCount(t, 4)
+	p, _ := NewSorter().SetStrategy(PairwiseMerge).Explain(KindFloat64, 1000000)
+	if p.Chunks != 16 || p.MergeLevels != 4 || p.RadixPasses != 0 {
+		t.Errorf("unexpected pairwise plan %+v", p)
+	}
+	p, _ = NewSorter().SetStrategy(KWayMerge).Explain(KindFloat64, 1000000)
+	if p.MergeLevels != 1 {
+		t.Errorf("unexpected k-way plan %+v", p)
+	}
+	p, _ = NewSorter().SetStrategy(Radix).Explain(KindUint16, 1000000)
+	if p.Strategy != Radix || p.Chunks != 4 || p.MergeLevels != 0 || p.RadixPasses != 2 {
+		t.Errorf("unexpected radix plan %+v", p)
+	}
+	p, _ = NewSorter().SetStrategy(Samplesort).Explain(KindFloat64, 1000000)
+	if p.Chunks != 4 || p.MergeLevels != 0 {
+		t.Errorf("unexpected samplesort plan %+v", p)
+	}
+	p, _ = NewSorter().SetStrategy(Radix).Explain(KindString, 1000000)
+	if p.Strategy != PairwiseMerge {
+		t.Errorf("expected Radix to fall back to PairwiseMerge for strings, got %+v", p)
+	}
+	p, _ = NewSorter().Explain(KindInt, 100)
+	if p.Strategy != Sequential || p.Chunks != 1 || p.MergeLevels != 0 || p.Reason != "below threshold" {
+		t.Errorf("unexpected small plan %+v", p)
+	}
+}
+
+func TestExplain_Budget(t *testing.T) {
+	withCoreCount(t, 4)
+	s := NewSorter().SetMaxExtraBytes(64 << 10)
+	p, err := s.Explain(KindInt, 1000000)
+	if err != nil || p.Strategy != InPlace || p.ExtraBytes > 64<<10 || p.Reason != "memory budget" {
+		t.Errorf("unexpected budget plan %+v, %v", p, err)
+	}
+	if _, err := NewSorter().SetMaxExtraBytes(1).Explain(KindInt, 1000000); !errors.Is(err, ErrMemoryBudget) {
+		t.Errorf("expected ErrMemoryBudget, got %v", err)
+	}
+}
+
+func TestPlan_String(t *testing.T) {
+	withCoreCount(t, 4)
+	prev := DefaultStrategy
+	DefaultStrategy = Auto
+	defer func() { DefaultStrategy = prev }()
+
+	p := Plan(KindInt, 1000000)
+	if p.Strategy != Radix || !p.DataDependent {
+		t.Errorf("unexpected Auto plan %+v", p)
+	}
+	s := p.String()
+	for _, want := range []string{"1000000 int", "4 cores", "Radix", "8 radix passes", "MiB extra memory"} {
+		if !strings.Contains(s, want) {
+			t.Errorf("summary %q does not contain %q", s, want)
+		}
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	for b, want := range map[int64]string{512: "512 B", 1536: "1.5 KiB", 3 << 20: "3.0 MiB", 5 << 30: "5.0 GiB"} {
+		if got := formatBytes(b); got != want {
+			t.Errorf("formatBytes(%d) = %q, want %q", b, got, want)
+		}
+	}
+}
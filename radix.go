@@ -0,0 +1,88 @@
+package parsort
+
+import (
+	"math"
+)
+
+// radixSort is a parallel least significant digit radix sort over the bytes of ops.key.
+// Every pass counts digits per block, computes the output offsets and scatters the blocks
+// in parallel. Passes where every key has the same digit are skipped.
+func radixSort[T any](data []T, ops *typeOps[T]) {
+	n := len(data)
+	blocks := ops.blocks(n)
+	counts := make([][256]int, len(blocks))
+	src, dst := data, ops.buffer(n)
+
+	for pass := 0; pass < ops.keyBytes; pass++ {
+		shift := uint(pass * 8)
+
+		runTasks(len(blocks), func(b int) {
+			ops.trace.region(regionRadixPass, func() {
+				c := &counts[b]
+				*c = [256]int{}
+				for _, v := range src[blocks[b].start:blocks[b].end] {
+					c[byte(ops.key(v)>>shift)]++
+				}
+			})
+		})
+
+		skip := false
+		pos := 0
+		for d := 0; d < 256; d++ {
+			total := 0
+			for b := range blocks {
+				c := counts[b][d]
+				counts[b][d] = pos
+				pos += c
+				total += c
+			}
+			if total == n {
+				skip = true
+				break
+			}
+		}
+		if skip {
+			continue
+		}
+
+		runTasks(len(blocks), func(b int) {
+			ops.trace.region(regionRadixPass, func() {
+				off := &counts[b]
+				for _, v := range src[blocks[b].start:blocks[b].end] {
+					d := byte(ops.key(v) >> shift)
+					dst[off[d]] = v
+					off[d]++
+				}
+			})
+		})
+		src, dst = dst, src
+	}
+
+	if &src[0] != &data[0] {
+		parallelCopy(data, src)
+	}
+}
+
+// float64Key maps a float64 to a key that orders like sort.Float64s, with NaNs first.
+func float64Key(v float64) uint64 {
+	if v != v {
+		return 0
+	}
+	b := math.Float64bits(v)
+	if b>>63 == 1 {
+		return ^b
+	}
+	return b | 1<<63
+}
+
+// float32Key maps a float32 to a key that orders like sort.Float64s, with NaNs first.
+func float32Key(v float32) uint64 {
+	if v != v {
+		return 0
+	}
+	b := math.Float32bits(v)
+	if b>>31 == 1 {
+		return uint64(^b)
+	}
+	return uint64(b | 1<<31)
+}
@@ -0,0 +1,92 @@
+package parsort
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ResortDirty restores the ascending order of data after the elements at dirtyIdx were modified,
+// given that the remaining elements are still sorted by less. Appended elements are resorted by
+// listing their indexes. The dirty elements are extracted, sorted in parallel and merged back,
+// which costs O(d log n) comparisons for d dirty indexes and moves only the elements from the
+// first affected position on, instead of sorting all of data.
+//
+// Dirty elements are placed after the clean elements they compare equal to, and keep the order
+// of their indexes among themselves. Repeated indexes are ignored, an index outside data panics.
+func ResortDirty[T any](data []T, dirtyIdx []int, less func(a, b T) bool) {
+	resortDirty(data, dirtyIdx, less, defaultSorter())
+}
+
+// ResortDirtyWith is ResortDirty using the configuration of s to sort the dirty elements.
+// It returns the errors of StructAscStableWith, leaving data untouched.
+func ResortDirtyWith[T any](s *Sorter, data []T, dirtyIdx []int, less func(a, b T) bool) error {
+	return resortDirty(data, dirtyIdx, less, s)
+}
+
+func resortDirty[T any](data []T, dirtyIdx []int, less func(a, b T) bool, s *Sorter) (err error) {
+	idx := append([]int(nil), dirtyIdx...)
+	sort.Ints(idx)
+	d := 0
+	for i, v := range idx {
+		if v < 0 || v >= len(data) {
+			panic(fmt.Sprintf("parsort: dirty index %d out of range [0:%d]", v, len(data)))
+		}
+		if i == 0 || v != idx[d-1] {
+			idx[d] = v
+			d++
+		}
+	}
+	idx = idx[:d]
+	if d == 0 {
+		return nil
+	}
+
+	dirty := make([]T, d)
+	for i, p := range idx {
+		dirty[i] = data[p]
+	}
+	if err := structSortStable(dirty, less, s); err != nil {
+		return err
+	}
+	if !s.raisePanics {
+		defer func() {
+			if r := recover(); r != nil {
+				err = newPanicError(r)
+			}
+		}()
+	}
+
+	// ranks[j] is the number of clean elements placed before dirty[j]. Clean elements are searched
+	// in place, skipping the dirty positions, so nothing is moved before every comparison is done.
+	clean := len(data) - d
+	at := func(c int) T {
+		k := sort.Search(d, func(k int) bool { return idx[k]-k > c })
+		return data[c+k]
+	}
+	ranks := make([]int, d)
+	parts := splitN(d, cores())
+	runTasks(len(parts), func(i int) {
+		for j := parts[i].start; j < parts[i].end; j++ {
+			ranks[j] = sort.Search(clean, func(c int) bool { return less(dirty[j], at(c)) })
+		}
+	})
+
+	// Remove the dirty positions, shifting the clean elements left.
+	w := idx[0]
+	for k, p := range idx {
+		end := len(data)
+		if k+1 < d {
+			end = idx[k+1]
+		}
+		w += copy(data[w:], data[p+1:end])
+	}
+
+	// Open a gap for every dirty element, from the right so that nothing is overwritten early.
+	end := clean
+	for j := d - 1; j >= 0; j-- {
+		copy(data[ranks[j]+j+1:end+j+1], data[ranks[j]:end])
+		data[ranks[j]+j] = dirty[j]
+		end = ranks[j]
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package parsort
+
+import (
+	"errors"
+	"math/rand"
+	"sort"
+	"sync/atomic"
+	"testing"
+)
+
+type score struct {
+	Player int
+	Points int
+}
+
+func sortedScores(n int) []score {
+	data := make([]score, n)
+	for i := range data {
+		data[i] = score{Player: i, Points: rand.Intn(n / 4)}
+	}
+	sort.SliceStable(data, func(i, j int) bool { return data[i].Points < data[j].Points })
+	return data
+}
+
+func byPoints(a, b score) bool { return a.Points < b.Points }
+
+func TestResortDirty(t *testing.T) {
+	withCoreCount(t, 4)
+	for _, dirtyCount := range []int{1, 10, 3000, 50000} {
+		data := sortedScores(200000)
+		var dirty []int
+		for i := 0; i < dirtyCount; i++ {
+			p := rand.Intn(len(data))
+			data[p].Points = rand.Intn(len(data) / 4)
+			dirty = append(dirty, p, p)
+		}
+		// Appended elements.
+		for i := 0; i < 5; i++ {
+			data = append(data, score{Player: -i, Points: rand.Intn(len(data) / 4)})
+			dirty = append(dirty, len(data)-1)
+		}
+		players := make(map[int]int)
+		for _, v := range data {
+			players[v.Player]++
+		}
+
+		ResortDirty(data, dirty, byPoints)
+		if !sort.SliceIsSorted(data, func(i, j int) bool { return data[i].Points < data[j].Points }) {
+			t.Fatalf("%d dirty: not sorted", dirtyCount)
+		}
+		for _, v := range data {
+			players[v.Player]--
+		}
+		for p, c := range players {
+			if c != 0 {
+				t.Fatalf("%d dirty: player %d count off by %d", dirtyCount, p, c)
+			}
+		}
+	}
+}
+
+func TestResortDirty_Placement(t *testing.T) {
+	data := []score{{1, 10}, {2, 20}, {3, 99}, {4, 30}, {5, 40}, {6, 0}}
+	data[2].Points = 20
+	data[5].Points = 20
+	ResortDirty(data, []int{5, 2}, byPoints)
+	want := []score{{1, 10}, {2, 20}, {3, 20}, {6, 20}, {4, 30}, {5, 40}}
+	for i := range want {
+		if data[i] != want[i] {
+			t.Fatalf("got %v, want %v", data, want)
+		}
+	}
+
+	ResortDirty(data, nil, byPoints)
+	if data[0] != want[0] {
+		t.Errorf("no dirty index changed data")
+	}
+}
+
+func TestResortDirty_OutOfRange(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected a panic for an index out of range")
+		}
+	}()
+	ResortDirty([]score{{1, 1}}, []int{1}, byPoints)
+}
+
+func TestResortDirtyWith_Panic(t *testing.T) {
+	withCoreCount(t, 4)
+	data := sortedScores(100000)
+	orig := append([]score(nil), data...)
+	var calls int32
+	err := ResortDirtyWith(NewSorter(), data, []int{5, 500, 5000}, func(a, b score) bool {
+		// Fails while searching the ranks, after the dirty elements are sorted.
+		if atomic.AddInt32(&calls, 1) > 5 {
+			panic("boom")
+		}
+		return a.Points < b.Points
+	})
+	var pe *PanicError
+	if !errors.As(err, &pe) {
+		t.Fatalf("expected a *PanicError, got %v", err)
+	}
+	for i := range data {
+		if data[i] != orig[i] {
+			t.Fatalf("data modified at %d", i)
+		}
+	}
+}
+
+func BenchmarkResortDirty(b *testing.B) {
+	base := sortedScores(1000000)
+	data := make([]score, len(base))
+	dirty := make([]int, 2000)
+	for i := range dirty {
+		dirty[i] = rand.Intn(len(base))
+	}
+	prepare := func() {
+		copy(data, base)
+		for _, p := range dirty {
+			data[p].Points = rand.Intn(len(base) / 4)
+		}
+	}
+	b.Run("ResortDirty", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			b.StopTimer()
+			prepare()
+			b.StartTimer()
+			ResortDirty(data, dirty, byPoints)
+		}
+	})
+	b.Run("StructAscStable", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			b.StopTimer()
+			prepare()
+			b.StartTimer()
+			StructAscStable(data, byPoints)
+		}
+	})
+}
@@ -0,0 +1,99 @@
+package parsort
+
+import (
+	"sort"
+)
+
+// sampleOversampling is the number of samples taken per bucket when picking splitters.
+const sampleOversampling = 32
+
+// sampleSort partitions the blocks of data into TasksPerCore buckets per block, delimited by
+// sampled splitters, then sorts the buckets in parallel. Elements keep their relative order
+// while being distributed, so the result is stable when ops.sort is stable.
+func sampleSort[T any](data []T, ops *typeOps[T]) {
+	n := len(data)
+	blocks := ops.blocks(n)
+	buckets := splitCount(n, tasksFor(len(blocks)))
+	if buckets <= 1 {
+		ops.sort(data)
+		return
+	}
+
+	splitters := sampleSplitters(data, buckets, ops.less)
+	bucketOf := func(v T) int {
+		return sort.Search(len(splitters), func(i int) bool {
+			return ops.less(v, splitters[i])
+		})
+	}
+
+	counts := make([][]int, len(blocks))
+	runTasks(len(blocks), func(b int) {
+		ops.trace.region(regionPartition, func() {
+			c := make([]int, buckets)
+			for _, v := range data[blocks[b].start:blocks[b].end] {
+				c[bucketOf(v)]++
+			}
+			counts[b] = c
+		})
+	})
+
+	// Bucket k of block b is written at offsets[b][k], blocks are laid out in input order within a bucket.
+	bounds := make([]chunk, buckets)
+	offsets := make([][]int, len(blocks))
+	for b := range blocks {
+		offsets[b] = make([]int, buckets)
+	}
+	pos := 0
+	for k := 0; k < buckets; k++ {
+		bounds[k].start = pos
+		for b := range blocks {
+			offsets[b][k] = pos
+			pos += counts[b][k]
+		}
+		bounds[k].end = pos
+	}
+
+	buffer := ops.buffer(n)
+	runTasks(len(blocks), func(b int) {
+		ops.trace.region(regionPartition, func() {
+			off := offsets[b]
+			for _, v := range data[blocks[b].start:blocks[b].end] {
+				k := bucketOf(v)
+				buffer[off[k]] = v
+				off[k]++
+			}
+		})
+	})
+
+	runTasks(buckets, func(k int) {
+		bk := bounds[k]
+		from := ops.trace.now()
+		ops.trace.region(regionChunkSort, func() {
+			ops.sort(buffer[bk.start:bk.end])
+		})
+		ops.trace.chunkSorted(from)
+		copy(data[bk.start:bk.end], buffer[bk.start:bk.end])
+	})
+}
+
+// sampleSplitters returns buckets-1 ascending splitters chosen from an evenly spaced sample of data.
+func sampleSplitters[T any](data []T, buckets int, less func(a, b T) bool) []T {
+	size := buckets * sampleOversampling
+	if size > len(data) {
+		size = len(data)
+	}
+	samples := make([]T, size)
+	step := len(data) / size
+	for i := range samples {
+		samples[i] = data[i*step+step/2]
+	}
+	sort.Slice(samples, func(i, j int) bool {
+		return less(samples[i], samples[j])
+	})
+
+	splitters := make([]T, buckets-1)
+	for i := range splitters {
+		splitters[i] = samples[((i+1)*size)/buckets]
+	}
+	return splitters
+}
@@ -0,0 +1,238 @@
+package parsort
+
+import (
+	"math/bits"
+	"sync"
+	"sync/atomic"
+)
+
+// taskRange is a contiguous range of task indexes owned by one worker.
+// Both bounds are packed into a single word so the owner and thieves can update it with one CAS.
+// It is padded to a cache line to keep workers from invalidating each other's ranges.
+type taskRange struct {
+	bounds uint64
+	_      [56]byte
+}
+
+func packRange(lo, hi uint32) uint64 {
+	return uint64(lo)<<32 | uint64(hi)
+}
+
+func unpackRange(v uint64) (lo, hi uint32) {
+	return uint32(v >> 32), uint32(v)
+}
+
+// pop takes the lowest task of the range, it is only called by the owner.
+func (x *taskRange) pop() (int, bool) {
+	for {
+		old := atomic.LoadUint64(&x.bounds)
+		lo, hi := unpackRange(old)
+		if lo >= hi {
+			return 0, false
+		}
+		if atomic.CompareAndSwapUint64(&x.bounds, old, packRange(lo+1, hi)) {
+			return int(lo), true
+		}
+	}
+}
+
+// steal takes the upper half of the range, rounded up, on behalf of another worker.
+func (x *taskRange) steal() (lo, hi uint32, ok bool) {
+	for {
+		old := atomic.LoadUint64(&x.bounds)
+		lo, hi := unpackRange(old)
+		if lo >= hi {
+			return 0, 0, false
+		}
+		mid := lo + (hi-lo)/2
+		if atomic.CompareAndSwapUint64(&x.bounds, old, packRange(lo, mid)) {
+			return mid, hi, true
+		}
+	}
+}
+
+// busyWorkers is the number of goroutines started by parsort that are still running, across all sorts.
+var busyWorkers int64
+
+// acquireWorkers reserves up to n goroutines from the budget set by MaxWorkers and returns how
+// many it got. It never blocks, a caller that gets fewer than it asked for does more work itself.
+func acquireWorkers(n int) int {
+	if n <= 0 {
+		return 0
+	}
+	limit := int64(MaxWorkers)
+	if limit <= 0 {
+		limit = int64(cores())
+	}
+	for {
+		cur := atomic.LoadInt64(&busyWorkers)
+		free := limit - cur
+		if free <= 0 {
+			return 0
+		}
+		if free > int64(n) {
+			free = int64(n)
+		}
+		if atomic.CompareAndSwapInt64(&busyWorkers, cur, cur+free) {
+			return int(free)
+		}
+	}
+}
+
+// releaseWorkers returns n goroutines to the budget.
+func releaseWorkers(n int) {
+	atomic.AddInt64(&busyWorkers, -int64(n))
+}
+
+// runTasks calls fn for every task index in [0, tasks) using up to cores() goroutines,
+// the calling goroutine included. Goroutines other than the caller come out of the shared
+// MaxWorkers budget, when it is exhausted the tasks run on the calling goroutine.
+// Every worker starts with an equal share of the indexes and steals half of another worker's
+// remaining share once its own is done, so a slow task only delays the tasks queued behind it
+// until someone else picks them up. A panic in fn stops the remaining tasks and is raised
+// again on the calling goroutine as a *PanicError once every worker has returned.
+func runTasks(tasks int, fn func(i int)) {
+	workers := cores()
+	if workers > tasks {
+		workers = tasks
+	}
+	if workers > 1 {
+		workers = 1 + acquireWorkers(workers-1)
+	}
+	if workers <= 1 {
+		for i := 0; i < tasks; i++ {
+			fn(i)
+		}
+		return
+	}
+
+	// The first panic stops the workers from starting new tasks, it is raised again once they are all done.
+	var (
+		stop    int32
+		once    sync.Once
+		failure *PanicError
+	)
+
+	ranges := make([]taskRange, workers)
+	for w := range ranges {
+		lo := uint32(w * tasks / workers)
+		hi := uint32((w + 1) * tasks / workers)
+		ranges[w].bounds = packRange(lo, hi)
+	}
+
+	work := func(w int) {
+		defer func() {
+			if r := recover(); r != nil {
+				atomic.StoreInt32(&stop, 1)
+				once.Do(func() {
+					failure = newPanicError(r)
+				})
+			}
+		}()
+
+		own := &ranges[w]
+		for atomic.LoadInt32(&stop) == 0 {
+			if i, ok := own.pop(); ok {
+				fn(i)
+				continue
+			}
+			stolen := false
+			for k := 1; k < workers && !stolen; k++ {
+				if lo, hi, ok := ranges[(w+k)%workers].steal(); ok {
+					// Our range is empty, nobody else writes to it until we publish the stolen tasks.
+					atomic.StoreUint64(&own.bounds, packRange(lo, hi))
+					stolen = true
+				}
+			}
+			if !stolen {
+				return
+			}
+		}
+	}
+
+	var wg sync.WaitGroup
+	for w := 1; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			defer releaseWorkers(1)
+			work(w)
+		}(w)
+	}
+	work(0)
+	wg.Wait()
+	if failure != nil {
+		panic(failure)
+	}
+}
+
+// mergeNode is an inner node of the merge tree built by runMergeTree.
+type mergeNode struct {
+	lo, mid, hi int // leaves covered by the left child [lo, mid) and the right child [mid, hi)
+	depth       int // 0 for the root
+	height      int // 1 for the merges of two leaves
+	parent      int // index of the parent node, -1 for the root
+	ready       int32
+}
+
+// mergeTreeDepth returns the depth of the deepest leaf of the merge tree over the given number of leaves.
+func mergeTreeDepth(leaves int) int {
+	if leaves <= 1 {
+		return 0
+	}
+	return bits.Len(uint(leaves - 1))
+}
+
+// runMergeTree runs leaf for every leaf in [0, leaves) with runTasks, and merge for every inner
+// node of a balanced binary tree over the leaves. There are no levels: whichever worker completes
+// the second child of a node runs the node's merge right away and then moves on to its parent,
+// so merges start as soon as both of their inputs are ready while other workers keep sorting.
+// Leaves and nodes receive their depth in the tree, the root being at depth 0.
+// Leaves are recorded on tr as chunk sorts and nodes as merges of the level of their height.
+func runMergeTree(leaves int, tr *sortTrace, leaf func(i, depth int), merge func(lo, mid, hi, depth int)) {
+	if leaves <= 0 {
+		return
+	}
+
+	nodes := make([]mergeNode, 0, leaves-1)
+	leafParent := make([]int, leaves)
+	leafDepth := make([]int, leaves)
+	var build func(lo, hi, depth, parent int) int
+	build = func(lo, hi, depth, parent int) int {
+		if hi-lo == 1 {
+			leafParent[lo] = parent
+			leafDepth[lo] = depth
+			return 0
+		}
+		idx := len(nodes)
+		mid := (lo + hi) / 2
+		nodes = append(nodes, mergeNode{lo: lo, mid: mid, hi: hi, depth: depth, parent: parent})
+		height := 1 + build(lo, mid, depth+1, idx)
+		if h := 1 + build(mid, hi, depth+1, idx); h > height {
+			height = h
+		}
+		nodes[idx].height = height
+		return height
+	}
+	build(0, leaves, 0, -1)
+
+	runTasks(leaves, func(i int) {
+		from := tr.now()
+		tr.region(regionChunkSort, func() {
+			leaf(i, leafDepth[i])
+		})
+		tr.chunkSorted(from)
+		for p := leafParent[i]; p >= 0; p = nodes[p].parent {
+			// The first child to finish leaves the merge to the second one.
+			if atomic.AddInt32(&nodes[p].ready, 1) < 2 {
+				return
+			}
+			from = tr.now()
+			node := &nodes[p]
+			tr.region(regionMerge, func() {
+				merge(node.lo, node.mid, node.hi, node.depth)
+			})
+			tr.merged(node.height, from)
+		}
+	})
+}
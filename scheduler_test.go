@@ -0,0 +1,231 @@
+package parsort
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRunTasks_EveryTaskOnce(t *testing.T) {
+	for _, cores := range []int{1, 2, 3, 8} {
+		withCoreCount(t, cores)
+		for _, tasks := range []int{0, 1, 2, 7, 64, 1000} {
+			counts := make([]int32, tasks)
+			runTasks(tasks, func(i int) {
+				atomic.AddInt32(&counts[i], 1)
+			})
+			for i, c := range counts {
+				if c != 1 {
+					t.Fatalf("cores=%d tasks=%d: task %d ran %d times", cores, tasks, i, c)
+				}
+			}
+		}
+	}
+}
+
+func TestRunTasks_StealsFromSlowWorker(t *testing.T) {
+	withCoreCount(t, 4)
+	// The first worker owns tasks 0..7 and is stuck on task 0, the others must finish its share.
+	var done int32
+	start := time.Now()
+	runTasks(32, func(i int) {
+		if i == 0 {
+			for atomic.LoadInt32(&done) < 31 {
+				if time.Since(start) > 5*time.Second {
+					return
+				}
+				time.Sleep(time.Millisecond)
+			}
+			return
+		}
+		atomic.AddInt32(&done, 1)
+	})
+	if atomic.LoadInt32(&done) != 31 {
+		t.Errorf("expected the other workers to steal the blocked worker's tasks, %d of 31 done", done)
+	}
+}
+
+func TestSplitTasks_Granularity(t *testing.T) {
+	withCoreCount(t, 8)
+
+	chunks := splitTasks(1 << 20)
+	if len(chunks) != 8*TasksPerCore {
+		t.Errorf("expected %d chunks, got %d", 8*TasksPerCore, len(chunks))
+	}
+
+	chunks = splitTasks(5 * MinChunkSize)
+	if len(chunks) != 5 {
+		t.Errorf("expected 5 chunks of at least MinChunkSize, got %d", len(chunks))
+	}
+	for _, ch := range chunks {
+		if ch.end-ch.start < MinChunkSize {
+			t.Errorf("chunk %v is smaller than MinChunkSize", ch)
+		}
+	}
+
+	chunks = splitTasks(100)
+	if len(chunks) != 1 || chunks[0] != (chunk{0, 100}) {
+		t.Errorf("expected a single chunk for a tiny input, got %v", chunks)
+	}
+
+	if got := len(splitChunks(1 << 20)); got != 8 {
+		t.Errorf("expected splitChunks to stay at CoreCount blocks, got %d", got)
+	}
+}
+
+func TestSplitTasks_Covers(t *testing.T) {
+	withCoreCount(t, 6)
+	for _, n := range []int{1, 2047, 2048, 12289, 100003} {
+		end := 0
+		for _, ch := range splitTasks(n) {
+			if ch.start != end || ch.end <= ch.start {
+				t.Fatalf("n=%d: chunk %v doesn't follow %d", n, ch, end)
+			}
+			end = ch.end
+		}
+		if end != n {
+			t.Errorf("n=%d: chunks end at %d", n, end)
+		}
+	}
+}
+
+func TestRunMergeTree_MergesAfterChildren(t *testing.T) {
+	for _, cores := range []int{1, 3, 8} {
+		withCoreCount(t, cores)
+		for _, leaves := range []int{1, 2, 5, 13, 32} {
+			// done[i] is the exclusive end of the range completed at leaf i.
+			done := make([]int32, leaves)
+			var merges int32
+			runMergeTree(leaves, nil, func(i, depth int) {
+				if depth > mergeTreeDepth(leaves) {
+					t.Errorf("leaf %d at depth %d, deeper than %d", i, depth, mergeTreeDepth(leaves))
+				}
+				atomic.StoreInt32(&done[i], int32(i+1))
+			}, func(lo, mid, hi, _ int) {
+				if atomic.LoadInt32(&done[lo]) != int32(mid) || atomic.LoadInt32(&done[mid]) != int32(hi) {
+					t.Errorf("merge of [%d, %d) and [%d, %d) started before its inputs were ready", lo, mid, mid, hi)
+				}
+				atomic.StoreInt32(&done[lo], int32(hi))
+				atomic.AddInt32(&merges, 1)
+			})
+			if done[0] != int32(leaves) || merges != int32(leaves-1) {
+				t.Errorf("cores=%d leaves=%d: root covers %d leaves after %d merges", cores, leaves, done[0], merges)
+			}
+		}
+	}
+}
+
+func TestRunMergeTree_NoLevelBarrier(t *testing.T) {
+	withCoreCount(t, 4)
+	// Leaf 7 is held back until the merge of leaves 0 and 1 ran, which only
+	// happens if merges don't wait for every leaf of their level.
+	merged := make(chan struct{})
+	var once int32
+	runMergeTree(8, nil, func(i, _ int) {
+		if i == 7 {
+			select {
+			case <-merged:
+			case <-time.After(5 * time.Second):
+				t.Errorf("merge of leaves 0 and 1 waited for leaf 7")
+			}
+		}
+	}, func(lo, mid, hi, _ int) {
+		if lo == 0 && hi == 2 && atomic.CompareAndSwapInt32(&once, 0, 1) {
+			close(merged)
+		}
+	})
+}
+
+func withMaxWorkers(t testing.TB, n int) {
+	prev := MaxWorkers
+	MaxWorkers = n
+	t.Cleanup(func() {
+		MaxWorkers = prev
+	})
+}
+
+func TestAcquireWorkers_Budget(t *testing.T) {
+	withCoreCount(t, 8)
+	withMaxWorkers(t, 5)
+
+	if got := acquireWorkers(3); got != 3 {
+		t.Fatalf("expected 3 workers, got %d", got)
+	}
+	if got := acquireWorkers(3); got != 2 {
+		t.Fatalf("expected the 2 remaining workers, got %d", got)
+	}
+	if got := acquireWorkers(1); got != 0 {
+		t.Fatalf("expected an exhausted budget, got %d", got)
+	}
+	releaseWorkers(5)
+	if got := atomic.LoadInt64(&busyWorkers); got != 0 {
+		t.Errorf("expected no busy workers, got %d", got)
+	}
+}
+
+func TestRunTasks_SequentialWhenBudgetExhausted(t *testing.T) {
+	withCoreCount(t, 8)
+	withMaxWorkers(t, 4)
+
+	held := acquireWorkers(4)
+	defer releaseWorkers(held)
+
+	var running, peak int32
+	runTasks(64, func(i int) {
+		r := atomic.AddInt32(&running, 1)
+		if r > atomic.LoadInt32(&peak) {
+			atomic.StoreInt32(&peak, r)
+		}
+		time.Sleep(100 * time.Microsecond)
+		atomic.AddInt32(&running, -1)
+	})
+	if peak != 1 {
+		t.Errorf("expected tasks to run on the calling goroutine only, %d ran at once", peak)
+	}
+}
+
+func TestMaxWorkers_ConcurrentSorts(t *testing.T) {
+	withCoreCount(t, 8)
+	withMaxWorkers(t, 3)
+
+	var peak int64
+	stop := make(chan struct{})
+	watched := make(chan struct{})
+	go func() {
+		defer close(watched)
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			if b := atomic.LoadInt64(&busyWorkers); b > peak {
+				peak = b
+			}
+		}
+	}()
+
+	done := make(chan struct{})
+	for g := 0; g < 20; g++ {
+		go func() {
+			defer func() { done <- struct{}{} }()
+			data := genPeople(60000)
+			StructAscStable(data, func(a, b person) bool { return a.Age < b.Age })
+			if !isSortedAsc(data) {
+				t.Errorf("concurrent StructAscStable failed to sort correctly")
+			}
+		}()
+	}
+	for g := 0; g < 20; g++ {
+		<-done
+	}
+	close(stop)
+	<-watched
+
+	if peak > 3 {
+		t.Errorf("expected at most 3 extra goroutines, saw %d", peak)
+	}
+	if b := atomic.LoadInt64(&busyWorkers); b != 0 {
+		t.Errorf("expected every worker to be released, %d still busy", b)
+	}
+}
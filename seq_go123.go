@@ -0,0 +1,187 @@
+//go:build go1.23
+
+package parsort
+
+import (
+	"cmp"
+	"iter"
+	"slices"
+	"sync"
+)
+
+// Sorted collects the values of seq and returns them sorted in ascending order.
+// Batches are sorted by the library's goroutines while seq is still being read,
+// and merged once it ends. NaNs are ordered before other values.
+func Sorted[T cmp.Ordered](seq iter.Seq[T]) []T {
+	return collectSorted(seq, orderedOps[T]())
+}
+
+// SortedFunc is Sorted for values ordered by less. The sort is not stable.
+func SortedFunc[T any](seq iter.Seq[T], less func(a, b T) bool) []T {
+	return collectSorted(seq, structOps(less, false))
+}
+
+// SortedSeq returns an iterator over the values of seq in ascending order.
+// seq is read and sorted as described by Sorted every time the iterator is ranged over.
+func SortedSeq[T cmp.Ordered](seq iter.Seq[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, v := range Sorted(seq) {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// SortedSeqFunc is SortedSeq for values ordered by less.
+func SortedSeqFunc[T any](seq iter.Seq[T], less func(a, b T) bool) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, v := range SortedFunc(seq, less) {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// SortedIndexed returns an iterator over the values of seq in ascending order, paired with
+// their position in seq. Equal values keep the order of seq.
+func SortedIndexed[T cmp.Ordered](seq iter.Seq[T]) iter.Seq2[T, int] {
+	return SortedIndexedFunc(seq, orderedOps[T]().less)
+}
+
+// SortedIndexedFunc is SortedIndexed for values ordered by less.
+func SortedIndexedFunc[T any](seq iter.Seq[T], less func(a, b T) bool) iter.Seq2[T, int] {
+	return func(yield func(T, int) bool) {
+		indexed := func(yield func(indexedValue[T]) bool) {
+			i := 0
+			for v := range seq {
+				if !yield(indexedValue[T]{v: v, i: i}) {
+					return
+				}
+				i++
+			}
+		}
+		sorted := collectSorted(indexed, structOps(func(a, b indexedValue[T]) bool {
+			if less(a.v, b.v) {
+				return true
+			}
+			return !less(b.v, a.v) && a.i < b.i
+		}, false))
+		for _, e := range sorted {
+			if !yield(e.v, e.i) {
+				return
+			}
+		}
+	}
+}
+
+// All returns an iterator over the values x has not returned yet, in order.
+func (x *Iterator[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for {
+			v, ok := x.Next()
+			if !ok || !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// indexedValue is a value of a sequence with its position.
+type indexedValue[T any] struct {
+	v T
+	i int
+}
+
+// orderedOps returns the building blocks of the package level functions for T, or generic ones
+// for the types they don't cover, such as named types.
+func orderedOps[T cmp.Ordered]() *typeOps[T] {
+	if ops := builtinOps[T](); ops != nil {
+		return ops
+	}
+	return &typeOps[T]{less: cmp.Less[T], sort: slices.Sort[[]T]}
+}
+
+// collectSorted reads seq into batches, sorting every full batch on a worker while the next one
+// is read, then merges the sorted batches. Batches start at MinChunkSize elements and double
+// every cores() batches, so long sequences don't end up with a deep merge tree.
+func collectSorted[T any](seq iter.Seq[T], ops *typeOps[T]) []T {
+	var (
+		batches [][]T
+		wg      sync.WaitGroup
+		once    sync.Once
+		failure any
+	)
+	sortBatch := func(b []T) {
+		defer func() {
+			if r := recover(); r != nil {
+				once.Do(func() { failure = newPanicError(r) })
+			}
+		}()
+		ops.sort(b)
+	}
+	flush := func(b []T) {
+		batches = append(batches, b)
+		if acquireWorkers(1) == 0 {
+			sortBatch(b)
+			return
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer releaseWorkers(1)
+			sortBatch(b)
+		}()
+	}
+
+	size := MinChunkSize
+	if size < 1 {
+		size = 1
+	}
+	var batch []T
+	func() {
+		// A panicking seq must not leave workers running on its batches.
+		defer wg.Wait()
+		for v := range seq {
+			if batch == nil {
+				batch = make([]T, 0, size)
+			}
+			batch = append(batch, v)
+			if len(batch) == size {
+				flush(batch)
+				batch = nil
+				if len(batches)%cores() == 0 {
+					size *= 2
+				}
+			}
+		}
+		if len(batch) > 0 {
+			flush(batch)
+		}
+	}()
+	if failure != nil {
+		panic(failure)
+	}
+
+	switch len(batches) {
+	case 0:
+		return nil
+	case 1:
+		return batches[0]
+	}
+
+	chunks := make([]chunk, len(batches))
+	n := 0
+	for i, b := range batches {
+		chunks[i] = chunk{n, n + len(b)}
+		n += len(b)
+	}
+	data := make([]T, n)
+	buffer := make([]T, n)
+	pingPongTree(data, buffer, chunks, ops, func(i int, dst []T) {
+		copy(dst[chunks[i].start:chunks[i].end], batches[i])
+		batches[i] = nil
+	})
+	return data
+}
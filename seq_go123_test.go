@@ -0,0 +1,113 @@
+//go:build go1.23
+
+package parsort
+
+import (
+	"errors"
+	"maps"
+	"slices"
+	"sort"
+	"testing"
+)
+
+func TestSorted(t *testing.T) {
+	withCoreCount(t, 4)
+	for _, n := range []int{0, 1, 100, 2048, 100000} {
+		data := genInts(n)
+		got := Sorted(slices.Values(data))
+		sort.Ints(data)
+		if !intSlicesEqual(got, data) {
+			t.Errorf("n=%d: Sorted result incorrect", n)
+		}
+	}
+
+	// Named types use the generic building blocks.
+	type celsius float64
+	temps := []celsius{3, -1, 2}
+	if got := Sorted(slices.Values(temps)); !slices.Equal(got, []celsius{-1, 2, 3}) {
+		t.Errorf("unexpected named type result %v", got)
+	}
+
+	m := map[string]int{"b": 2, "c": 3, "a": 1}
+	if got := Sorted(maps.Keys(m)); !slices.Equal(got, []string{"a", "b", "c"}) {
+		t.Errorf("unexpected map keys %v", got)
+	}
+}
+
+func TestSortedFunc(t *testing.T) {
+	withCoreCount(t, 4)
+	people := genPeople(50000)
+	got := SortedFunc(slices.Values(people), func(a, b person) bool { return a.Age < b.Age })
+	if len(got) != len(people) || !isSortedAsc(got) {
+		t.Errorf("SortedFunc result incorrect")
+	}
+}
+
+func TestSortedSeq(t *testing.T) {
+	withCoreCount(t, 4)
+	data := genFloats(30000)
+	got := slices.Collect(SortedSeq(slices.Values(data)))
+	sort.Float64s(data)
+	if !floatSlicesEqual(got, data) {
+		t.Errorf("SortedSeq result incorrect")
+	}
+
+	// Stopping early.
+	count := 0
+	for range SortedSeqFunc(slices.Values(genPeople(100)), func(a, b person) bool { return a.Age < b.Age }) {
+		count++
+		if count == 10 {
+			break
+		}
+	}
+	if count != 10 {
+		t.Errorf("expected to stop after 10 values, got %d", count)
+	}
+}
+
+func TestSortedIndexed(t *testing.T) {
+	withCoreCount(t, 4)
+	data := make([]int, 50000)
+	for i := range data {
+		data[i] = genInts(1)[0] % 100
+	}
+	prev, prevIdx := -1, -1
+	seen := make([]bool, len(data))
+	for v, i := range SortedIndexed(slices.Values(data)) {
+		if data[i] != v || seen[i] {
+			t.Fatalf("value %d paired with wrong or repeated index %d", v, i)
+		}
+		seen[i] = true
+		if v < prev || (v == prev && i < prevIdx) {
+			t.Fatalf("out of order at value %d index %d", v, i)
+		}
+		prev, prevIdx = v, i
+	}
+}
+
+func TestSorted_Panic(t *testing.T) {
+	withCoreCount(t, 4)
+	defer func() {
+		var pe *PanicError
+		if err, ok := recover().(error); !ok || !errors.As(err, &pe) {
+			t.Errorf("expected a *PanicError, got %v", err)
+		}
+	}()
+	SortedFunc(slices.Values(genPeople(20000)), func(a, b person) bool {
+		if a.Age == 50 {
+			panic("boom")
+		}
+		return a.Age < b.Age
+	})
+}
+
+func TestIterator_All(t *testing.T) {
+	withCoreCount(t, 4)
+	data := genFloats(30000)
+	it := Float64Lazy(data)
+	first, _ := it.Next()
+	rest := slices.Collect(it.All())
+	if len(rest) != len(data)-1 || first > rest[0] || !sort.Float64sAreSorted(rest) {
+		t.Errorf("Iterator.All result incorrect")
+	}
+}
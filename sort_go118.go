@@ -0,0 +1,23 @@
+//go:build !go1.21
+
+package parsort
+
+import (
+	"sort"
+	"time"
+)
+
+// sortOrdered sorts data in ascending order with sort.Slice.
+// NaNs are ordered before other values.
+func sortOrdered[T ordered](data []T) {
+	sort.Slice(data, func(i, j int) bool {
+		return data[i] < data[j] || (data[i] != data[i] && data[j] == data[j])
+	})
+}
+
+// sortTimes sorts data in ascending order with sort.Slice.
+func sortTimes(data []time.Time) {
+	sort.Slice(data, func(i, j int) bool {
+		return data[i].Before(data[j])
+	})
+}
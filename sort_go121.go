@@ -0,0 +1,22 @@
+//go:build go1.21
+
+package parsort
+
+import (
+	"cmp"
+	"slices"
+	"time"
+)
+
+// sortOrdered sorts data in ascending order with the generic pdqsort of the slices package.
+// NaNs are ordered before other values.
+func sortOrdered[T cmp.Ordered](data []T) {
+	slices.Sort(data)
+}
+
+// sortTimes sorts data in ascending order with the generic pdqsort of the slices package.
+func sortTimes(data []time.Time) {
+	slices.SortFunc(data, func(a, b time.Time) int {
+		return a.Compare(b)
+	})
+}
@@ -0,0 +1,41 @@
+package parsort
+
+import (
+	"math"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestSortOrdered_NaNFirst(t *testing.T) {
+	data := []float32{3, float32(math.NaN()), 1, -2}
+	sortOrdered(data)
+	if data[0] == data[0] || data[1] != -2 || data[2] != 1 || data[3] != 3 {
+		t.Errorf("expected NaN first followed by ascending values, got %v", data)
+	}
+}
+
+func TestSortOrdered_Random(t *testing.T) {
+	data := genUint16s(10000)
+	sortOrdered(data)
+	if !sort.SliceIsSorted(data, func(i, j int) bool { return data[i] < data[j] }) {
+		t.Errorf("slice not sorted")
+	}
+}
+
+func TestSortTimes(t *testing.T) {
+	data := genTimes(10000)
+	sortTimes(data)
+	if !sort.SliceIsSorted(data, func(i, j int) bool { return data[i].Before(data[j]) }) {
+		t.Errorf("slice not sorted")
+	}
+}
+
+func TestSortTimes_Equal(t *testing.T) {
+	base := time.Now()
+	data := []time.Time{base.Add(time.Second), base, base, base.Add(-time.Second)}
+	sortTimes(data)
+	if !data[0].Equal(base.Add(-time.Second)) || !data[1].Equal(base) || !data[2].Equal(base) {
+		t.Errorf("unexpected order %v", data)
+	}
+}
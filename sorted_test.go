@@ -0,0 +1,131 @@
+package parsort
+
+import (
+	"errors"
+	"sort"
+	"testing"
+)
+
+func TestIntSorted_Strategies(t *testing.T) {
+	withCoreCount(t, 4)
+	for _, st := range strategies {
+		src := genInts(100000)
+		orig := append([]int(nil), src...)
+		expected := append([]int(nil), src...)
+		sort.Ints(expected)
+
+		got, err := NewSorter().SetStrategy(st).IntSorted(src)
+		if err != nil {
+			t.Fatalf("%s: %v", st, err)
+		}
+		if !intSlicesEqual(got, expected) {
+			t.Errorf("%s: sorted copy incorrect", st)
+		}
+		if !intSlicesEqual(src, orig) {
+			t.Errorf("%s: source modified", st)
+		}
+	}
+}
+
+func TestSortedInto(t *testing.T) {
+	withCoreCount(t, 4)
+	src := genFloats(50000)
+	orig := append([]float64(nil), src...)
+	dst := make([]float64, len(src))
+	Float64SortedInto(dst, src)
+	if !sort.Float64sAreSorted(dst) || !floatSlicesEqual(src, orig) {
+		t.Errorf("Float64SortedInto failed")
+	}
+
+	// Sorting a slice into itself sorts it in place.
+	Float64SortedInto(src, src)
+	if !floatSlicesEqual(src, dst) {
+		t.Errorf("Float64SortedInto in place failed")
+	}
+
+	times := genTimes(30000)
+	sorted := TimeSorted(times)
+	if !sort.SliceIsSorted(sorted, func(i, j int) bool { return sorted[i].Before(sorted[j]) }) {
+		t.Errorf("TimeSorted failed")
+	}
+	if s := StringSorted(nil); len(s) != 0 {
+		t.Errorf("expected empty result, got %v", s)
+	}
+	if s := Uint8Sorted([]uint8{3, 1, 2}); s[0] != 1 || s[1] != 2 || s[2] != 3 {
+		t.Errorf("Uint8Sorted failed: %v", s)
+	}
+}
+
+func TestSortedInto_LengthMismatch(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected a panic for a short destination")
+		}
+	}()
+	IntSortedInto(make([]int, 2), []int{3, 2, 1})
+}
+
+func TestSortedInto_Budget(t *testing.T) {
+	withCoreCount(t, 4)
+	src := genInts(100000)
+	dst := make([]int, len(src))
+	err := NewSorter().SetMaxExtraBytes(1).IntSortedInto(dst, src)
+	if !errors.Is(err, ErrMemoryBudget) {
+		t.Fatalf("expected ErrMemoryBudget, got %v", err)
+	}
+	for _, v := range dst {
+		if v != 0 {
+			t.Fatalf("destination written despite the error")
+		}
+	}
+}
+
+func TestStructSorted(t *testing.T) {
+	withCoreCount(t, 4)
+	for _, st := range strategies {
+		s := NewSorter().SetStrategy(st).SetVerify(true, func(err *VerifyError) {
+			t.Errorf("%s: %v", st, err)
+		})
+		src := genPeople(50000)
+		orig := append([]person(nil), src...)
+		got, err := StructSortedWith(s, src, func(a, b person) bool { return a.Age < b.Age })
+		if err != nil {
+			t.Fatalf("%s: %v", st, err)
+		}
+		if !isSortedAsc(got) {
+			t.Errorf("%s: StructSortedWith failed to sort", st)
+		}
+		for i := range src {
+			if src[i] != orig[i] {
+				t.Fatalf("%s: source modified", st)
+			}
+		}
+	}
+
+	src := genPeople(20000)
+	dst := make([]person, len(src))
+	StructSortedInto(dst, src, func(a, b person) bool { return a.Age < b.Age })
+	if !isSortedAsc(dst) || isSortedAsc(src) {
+		t.Errorf("StructSortedInto failed")
+	}
+	if got := StructSorted(src, func(a, b person) bool { return a.Age < b.Age }); !isSortedAsc(got) {
+		t.Errorf("StructSorted failed")
+	}
+}
+
+func BenchmarkIntSorted(b *testing.B) {
+	src := genInts(1000000)
+	b.Run("CopyThenSort", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			dst := append([]int(nil), src...)
+			IntAsc(dst)
+		}
+	})
+	b.Run("Sorted", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			IntSorted(src)
+		}
+	})
+}
@@ -0,0 +1,315 @@
+package parsort
+
+import (
+	"context"
+	"fmt"
+	"runtime/pprof"
+	"runtime/trace"
+	"sync/atomic"
+	"time"
+)
+
+// Sorter carries a sorting configuration that is used instead of the package level defaults.
+// A Sorter may be shared between goroutines once it is configured. The zero Sorter can sort,
+// but it doesn't keep its LastDecision.
+//
+// Example:
+//
+//	s := parsort.NewSorter().SetStrategy(parsort.Radix)
+//	s.IntAsc(data)
+type Sorter struct {
+	strategy      Strategy
+	maxExtraBytes int64
+	checkLess     bool
+	verify        bool
+	verifyHook    func(err *VerifyError)
+	observer      Observer
+	indirectSize  int
+	ctx           context.Context
+	decisions     *decisionLog
+	// raisePanics raises panics on the calling goroutine instead of returning them as errors, it is
+	// set for the package functions and not for the Sorter methods.
+	raisePanics bool
+}
+
+// NewSorter returns a Sorter initialised from the package level defaults.
+func NewSorter() *Sorter {
+	return &Sorter{
+		strategy:     DefaultStrategy,
+		checkLess:    CheckLess,
+		verify:       Verify,
+		verifyHook:   VerifyHook,
+		observer:     DefaultObserver,
+		indirectSize: StructIndirectSize,
+		decisions:    &decisionLog{},
+	}
+}
+
+// SetStrategy sets the algorithm used for slices above their MinParallelSize threshold.
+func (x *Sorter) SetStrategy(s Strategy) *Sorter {
+	x.strategy = s
+	return x
+}
+
+// Strategy returns the configured strategy.
+func (x *Sorter) Strategy() Strategy {
+	return x.strategy
+}
+
+// SetMaxExtraBytes limits the memory a sort may allocate to b bytes, as estimated by EstimateExtraBytes.
+// Within the limit, the configured strategy is replaced by PingPongMerge, InPlace, the same with
+// fewer chunks, and finally Sequential. When none of them fits, the sort methods return an error
+// wrapping ErrMemoryBudget. A value of 0 or less removes the limit.
+func (x *Sorter) SetMaxExtraBytes(b int64) *Sorter {
+	x.maxExtraBytes = b
+	return x
+}
+
+// MaxExtraBytes returns the configured memory limit, 0 when there is none.
+func (x *Sorter) MaxExtraBytes() int64 {
+	return x.maxExtraBytes
+}
+
+// SetCheckLess enables or disables the strict weak ordering check of CheckLess for the struct
+// functions called with x. NewSorter initialises it from CheckLess.
+func (x *Sorter) SetCheckLess(on bool) *Sorter {
+	x.checkLess = on
+	return x
+}
+
+// SetVerify enables or disables the output verification of Verify for sorts made with x, and sets
+// the hook receiving its failures. A nil hook logs them. NewSorter initialises both from Verify and VerifyHook.
+func (x *Sorter) SetVerify(on bool, hook func(err *VerifyError)) *Sorter {
+	x.verify = on
+	x.verifyHook = hook
+	return x
+}
+
+// SetObserver sets the Observer receiving the events of the sorts made with x, nil disables them.
+func (x *Sorter) SetObserver(o Observer) *Sorter {
+	x.observer = o
+	return x
+}
+
+// SetIndirectSize sets the element size in bytes from which the struct functions called with x
+// sort indirectly, see StructIndirectSize. 1 sorts every struct indirectly, 0 never does.
+// NewSorter initialises it from StructIndirectSize.
+func (x *Sorter) SetIndirectSize(bytes int) *Sorter {
+	x.indirectSize = bytes
+	return x
+}
+
+// IndirectSize returns the element size from which structs are sorted indirectly, 0 when they never are.
+func (x *Sorter) IndirectSize() int {
+	return x.indirectSize
+}
+
+// WithContext returns a copy of x whose sorts run with the pprof labels of ctx, plus a "parsort"
+// label holding the element type, on the calling goroutine and on every worker goroutine, so that
+// CPU profiles attribute sorting time to the right request. The runtime/trace task of every sort is
+// created as a child of the task of ctx. The copy shares its LastDecision with x.
+//
+// Sorts don't start once ctx is done, and parallel sorts stop at the next chunk sort, merge or
+// pass, returning ctx.Err(). The contents of a slice whose sort was stopped are unspecified.
+func (x *Sorter) WithContext(ctx context.Context) *Sorter {
+	c := *x
+	c.ctx = ctx
+	return &c
+}
+
+// LastDecision returns the decision of the most recent sort call made through x, or the zero
+// Decision for a Sorter not created by NewSorter.
+func (x *Sorter) LastDecision() Decision {
+	return x.decisions.get()
+}
+
+// defaultSorter returns a Sorter reflecting the current package level defaults.
+func defaultSorter() *Sorter {
+	return &Sorter{
+		strategy:     DefaultStrategy,
+		checkLess:    CheckLess,
+		verify:       Verify,
+		verifyHook:   VerifyHook,
+		observer:     DefaultObserver,
+		indirectSize: StructIndirectSize,
+		decisions:    &packageDecisions,
+		raisePanics:  true,
+	}
+}
+
+// returningSorter returns the package configuration returning panics as errors instead of
+// raising them, for the package level functions that report errors, such as the Async ones.
+func returningSorter() *Sorter {
+	s := defaultSorter()
+	s.raisePanics = false
+	return s
+}
+
+// sortSlice sorts data with ops following the configuration of s, in descending order when reverse is set.
+// Panics are returned as a *PanicError unless s.raisePanics is set.
+func sortSlice[T any](data []T, reverse bool, s *Sorter, threshold int, ops *typeOps[T]) (err error) {
+	if !s.raisePanics {
+		defer func() {
+			if r := recover(); r != nil {
+				err = newPanicError(r)
+				if canceled(r) {
+					err = s.ctx.Err()
+				}
+			}
+		}()
+	}
+
+	if s.ctx != nil {
+		if cerr := s.ctx.Err(); cerr != nil {
+			return cerr
+		}
+	}
+
+	input := data
+	if ops.src != nil {
+		input = ops.src
+	}
+	if s.checkLess && ops.userLess {
+		if cerr := checkStrictWeakOrder(input, ops.less); cerr != nil {
+			if s.raisePanics {
+				panic(cerr)
+			}
+			return cerr
+		}
+	}
+
+	if s.ctx != nil {
+		// Goroutines inherit the labels of the goroutine starting them, setting them on the
+		// calling goroutine labels every worker.
+		pprof.Do(s.ctx, pprof.Labels("parsort", ops.elemName()), func(ctx context.Context) {
+			err = sortSliceContext(ctx, data, reverse, s, threshold, ops)
+		})
+		return err
+	}
+	return sortSliceContext(context.Background(), data, reverse, s, threshold, ops)
+}
+
+// sortSliceInto sorts src into dst with ops following the configuration of s, leaving src untouched.
+// dst must have the length of src and must either be src or not overlap it.
+func sortSliceInto[T any](dst, src []T, s *Sorter, threshold int, ops *typeOps[T]) error {
+	if len(dst) != len(src) {
+		panic(fmt.Sprintf("parsort: destination length %d differs from source length %d", len(dst), len(src)))
+	}
+	if len(src) == 0 || &dst[0] == &src[0] {
+		return sortSlice(dst, false, s, threshold, ops)
+	}
+	o := *ops
+	o.src = src
+	return sortSlice(dst, false, s, threshold, &o)
+}
+
+// sortSliceContext is sortSlice once the labels of ctx are applied.
+func sortSliceContext[T any](ctx context.Context, data []T, reverse bool, s *Sorter, threshold int, ops *typeOps[T]) error {
+	metered := atomic.LoadInt32(&metricsOn) != 0
+	var start time.Time
+	if s.observer != nil || metered {
+		start = time.Now()
+	}
+
+	input := data
+	if ops.src != nil {
+		input = ops.src
+	}
+	d, err := decide(s, input, threshold, ops)
+	if err != nil {
+		return err
+	}
+	if ops.decided != nil {
+		*ops.decided = d
+	}
+
+	var tr *sortTrace
+	if s.observer != nil {
+		tr = newSortTrace(start, d.Chunks)
+		s.observer.Observe(Event{Kind: EventDecision, Type: ops.elemName(), Decision: d})
+	}
+	if trace.IsEnabled() {
+		var task *trace.Task
+		ctx, task = trace.NewTask(ctx, "parsort")
+		defer task.End()
+		trace.Logf(ctx, "parsort", "sorting %d %s with %v in %d chunks", d.N, ops.elemName(), d.Strategy, d.Chunks)
+		if tr == nil {
+			tr = newSortTrace(start, d.Chunks)
+		}
+		tr.ctx = ctx
+	}
+	if done := ctx.Done(); done != nil {
+		if tr == nil {
+			tr = newSortTrace(start, d.Chunks)
+		}
+		tr.done = done
+	}
+
+	var hash uint64
+	if s.verify {
+		hash = multisetHash(input)
+	}
+
+	// The strategies assume at least two elements, whatever MinParallelSize says.
+	if d.Strategy == Sequential || len(data) < 2 {
+		if ops.src != nil {
+			copy(data, ops.src)
+		}
+		ops.sort(data)
+	} else {
+		if reduced := d.Chunks != chunkCount(d.Strategy, len(data)); reduced || tr != nil {
+			o := *ops
+			if reduced {
+				o.chunks = d.Chunks
+			}
+			o.trace = tr
+			ops = &o
+		}
+		sortStrategy(data, d.Strategy, ops)
+	}
+	if reverse {
+		ops.reverse(data)
+	}
+
+	if s.verify {
+		verifySorted(s, data, reverse, hash, d, ops)
+	}
+	if s.observer != nil {
+		tr.emit(s.observer, Event{Type: ops.elemName(), Decision: d})
+	}
+	if metered {
+		recordMetrics(ops.elemName(), d, time.Since(start))
+	}
+	return nil
+}
+
+// typeName returns the name of T as printed by fmt.
+func typeName[T any]() string {
+	return fmt.Sprintf("%T", *new(T))
+}
+
+// verifySorted checks data, sorted by sortSlice, against the hash of the input and reports failures.
+func verifySorted[T any](s *Sorter, data []T, reverse bool, hash uint64, d Decision, ops *typeOps[T]) {
+	less := ops.less
+	if reverse {
+		less = func(a, b T) bool {
+			return ops.less(b, a)
+		}
+	}
+	reportUnverified(s, ops.elemName(), d, firstUnsorted(data, less), multisetHash(data) == hash)
+}
+
+// reportUnverified reports the failure of a sort of d.N elements of the named type, unless its
+// output is sorted, unsorted being -1, and a permutation of its input.
+func reportUnverified(s *Sorter, name string, d Decision, unsorted int, permutation bool) {
+	if unsorted < 0 && permutation {
+		return
+	}
+	reportVerifyError(s, &VerifyError{
+		Type:           name,
+		N:              d.N,
+		Decision:       d,
+		Unsorted:       unsorted,
+		NotPermutation: !permutation,
+	})
+}
@@ -0,0 +1,336 @@
+package parsort
+
+import (
+	"strconv"
+	"unsafe"
+)
+
+// Strategy selects the parallel algorithm used once a slice is above its MinParallelSize threshold.
+type Strategy int
+
+const (
+	// PairwiseMerge sorts chunks in parallel and merges them in parallel pairwise steps.
+	// It is the default and the algorithm described in the README.
+	PairwiseMerge Strategy = iota
+
+	// PingPongMerge works like PairwiseMerge but merges back and forth between the input
+	// and a single buffer instead of allocating a new slice for every merge.
+	PingPongMerge
+
+	// ChunkCopyMerge copies every chunk into a buffer before sorting it, so the input stays
+	// untouched until the merge phase starts writing the result back.
+	ChunkCopyMerge
+
+	// KWayMerge sorts the chunks in parallel and then merges all of them at once, with each
+	// core producing a disjoint range of the output.
+	KWayMerge
+
+	// Samplesort partitions the input into buckets using sampled splitters and then
+	// sorts each bucket in parallel. No merge phase is needed.
+	Samplesort
+
+	// Radix uses a parallel least significant digit radix sort.
+	// It only applies to integer and floating point types, other types fall back to PairwiseMerge.
+	Radix
+
+	// InPlace sorts the chunks in parallel and merges them with rotations, without a merge buffer.
+	// It trades speed for allocating almost nothing.
+	InPlace
+
+	// Sequential sorts on the calling goroutine regardless of the slice length.
+	Sequential
+
+	// Auto samples every input and picks between Sequential, PairwiseMerge, Radix, Samplesort
+	// and InPlace. The choice can be inspected with LastDecision or Sorter.LastDecision.
+	Auto
+)
+
+func (x Strategy) String() string {
+	switch x {
+	case PairwiseMerge:
+		return "PairwiseMerge"
+	case PingPongMerge:
+		return "PingPongMerge"
+	case ChunkCopyMerge:
+		return "ChunkCopyMerge"
+	case KWayMerge:
+		return "KWayMerge"
+	case Samplesort:
+		return "Samplesort"
+	case Radix:
+		return "Radix"
+	case InPlace:
+		return "InPlace"
+	case Sequential:
+		return "Sequential"
+	case Auto:
+		return "Auto"
+	}
+	return "Strategy(" + strconv.Itoa(int(x)) + ")"
+}
+
+// typeOps holds the building blocks a strategy needs for a given element type.
+type typeOps[T any] struct {
+	// less reports whether a sorts before b.
+	less func(a, b T) bool
+	// sort sorts a chunk sequentially. It must be stable when stable is set.
+	sort func(data []T)
+	// merge merges two sorted chunks into a newly allocated slice, used by PairwiseMerge.
+	merge func(a, b []T) []T
+	// key maps a value to an order preserving unsigned key, used by Radix. Nil when Radix doesn't apply.
+	key func(v T) uint64
+	// keyBytes is the number of significant low bytes returned by key.
+	keyBytes int
+	// prefix returns the length of the common prefix of a and b, used by Auto. Nil for non string types.
+	prefix func(a, b T) int
+	// stable is set when elements that compare equal must keep their relative order.
+	stable bool
+	// reverse reverses a sorted slice for the descending functions. Nil for structs, which reverse less instead.
+	reverse func(data []T)
+	// userLess is set when less comes from the caller, it is then checked when CheckLess is enabled.
+	userLess bool
+	// trace records the phases of the sort for an Observer, nil when there is none.
+	trace *sortTrace
+	// chunks overrides the number of chunks created by split when it is not 0.
+	chunks int
+	// indirect is set when data is an array of indexes standing for the elements being sorted.
+	indirect bool
+	// name is the element type reported to observers, metrics and profiles when it isn't T,
+	// as for index arrays. Empty otherwise.
+	name string
+	// decided, when set, receives the Decision of the sort.
+	decided *Decision
+	// src, when set, is the input of a sort into data: the contents of data are ignored and src
+	// is only read. The merge strategies sort their chunks out of src and merge into data.
+	src []T
+}
+
+// split splits n elements into the chunks sorted by the chunk sorting phase.
+func (x *typeOps[T]) split(n int) []chunk {
+	if x.chunks > 0 {
+		return splitN(n, x.chunks)
+	}
+	return splitTasks(n)
+}
+
+// blocks splits n elements into the blocks Radix and Samplesort partition in parallel.
+func (x *typeOps[T]) blocks(n int) []chunk {
+	if x.chunks > 0 {
+		return splitN(n, x.chunks)
+	}
+	return splitChunks(n)
+}
+
+// elemName returns the name of the type of the elements being sorted.
+func (x *typeOps[T]) elemName() string {
+	if x.name != "" {
+		return x.name
+	}
+	return typeName[T]()
+}
+
+// resolve returns the strategy that will actually run for the given ops.
+func (x *typeOps[T]) resolve(s Strategy) Strategy {
+	return resolveStrategy(s, x.key != nil)
+}
+
+// resolveStrategy returns the strategy that will actually run for s, key tells whether the type has a radix key.
+func resolveStrategy(s Strategy, key bool) Strategy {
+	switch s {
+	case PairwiseMerge, PingPongMerge, ChunkCopyMerge, KWayMerge, Samplesort, InPlace:
+		return s
+	case Radix:
+		if key {
+			return Radix
+		}
+	}
+	return PairwiseMerge
+}
+
+// sortStrategy sorts data in ascending order of ops.less using the given strategy.
+func sortStrategy[T any](data []T, s Strategy, ops *typeOps[T]) {
+	if ops.src != nil {
+		switch ops.resolve(s) {
+		case PairwiseMerge, PingPongMerge, ChunkCopyMerge:
+			// The first pass copies the chunks out of src and the root merge writes into data,
+			// nothing is copied back.
+			pingPongMergeSort(data, ops)
+			return
+		}
+		parallelCopy(data, ops.src)
+	}
+	switch ops.resolve(s) {
+	case PingPongMerge:
+		pingPongMergeSort(data, ops)
+	case ChunkCopyMerge:
+		chunkCopyMergeSort(data, ops)
+	case KWayMerge:
+		kWayMergeSort(data, ops)
+	case Samplesort:
+		sampleSort(data, ops)
+	case Radix:
+		radixSort(data, ops)
+	case InPlace:
+		inPlaceMergeSort(data, ops)
+	default:
+		if ops.merge == nil {
+			// Structs have no allocating merge, PairwiseMerge ping-pongs between data and one buffer.
+			pingPongMergeSort(data, ops)
+			return
+		}
+		pairwiseMergeSort(data, ops)
+	}
+}
+
+// splitChunks splits n elements into at most cores() contiguous blocks of equal size.
+// Blocks are only made smaller than MinChunkSize when n itself is.
+func splitChunks(n int) []chunk {
+	return splitN(n, cores())
+}
+
+// splitTasks splits n elements into the chunks sorted independently by the chunk sorting phase.
+// With more than one core it creates TasksPerCore chunks per core, so that runTasks can balance
+// uneven chunks between the cores. Chunks are only made smaller than MinChunkSize when n itself is.
+func splitTasks(n int) []chunk {
+	return splitN(n, taskParts())
+}
+
+// taskCount returns len(splitTasks(n)) without allocating.
+func taskCount(n int) int {
+	return splitCount(n, taskParts())
+}
+
+func taskParts() int {
+	return tasksFor(cores())
+}
+
+// tasksFor returns the number of tasks created for parts parts, TasksPerCore per part.
+func tasksFor(parts int) int {
+	if parts > 1 && TasksPerCore > 1 {
+		parts *= TasksPerCore
+	}
+	return parts
+}
+
+// chunkCount returns the number of chunks strategy st splits n elements into: one block per
+// core for Radix and Samplesort, the chunks of splitTasks for the other parallel strategies.
+func chunkCount(st Strategy, n int) int {
+	switch st {
+	case Sequential:
+		return 1
+	case Radix, Samplesort:
+		return splitCount(n, cores())
+	}
+	return taskCount(n)
+}
+
+// splitSize returns the chunk size used by splitN.
+func splitSize(n, parts int) int {
+	if MinChunkSize > 0 && n/MinChunkSize < parts {
+		parts = n / MinChunkSize
+	}
+	if parts < 1 {
+		parts = 1
+	}
+	return (n + parts - 1) / parts
+}
+
+// splitCount returns len(splitN(n, parts)) without allocating.
+func splitCount(n, parts int) int {
+	if n == 0 {
+		return 0
+	}
+	size := splitSize(n, parts)
+	return (n + size - 1) / size
+}
+
+func splitN(n, parts int) []chunk {
+	chunkSize := splitSize(n, parts)
+	chunks := make([]chunk, 0, splitCount(n, parts))
+	for i := 0; i < n; i += chunkSize {
+		end := i + chunkSize
+		if end > n {
+			end = n
+		}
+		chunks = append(chunks, chunk{i, end})
+	}
+	return chunks
+}
+
+// sortChunks sorts every chunk of data with runTasks.
+func sortChunks[T any](data []T, chunks []chunk, ops *typeOps[T]) {
+	runTasks(len(chunks), func(i int) {
+		from := ops.trace.now()
+		ops.trace.region(regionChunkSort, func() {
+			ops.sort(data[chunks[i].start:chunks[i].end])
+		})
+		ops.trace.chunkSorted(from)
+	})
+}
+
+// pairwiseMergeSort is the multiway parallel merge sort with pairwise merging described in the README.
+// Every merge allocates its result and starts as soon as both of its inputs are sorted.
+func pairwiseMergeSort[T any](data []T, ops *typeOps[T]) {
+	chunks := ops.split(len(data))
+
+	// A node's result is stored at the index of its leftmost leaf.
+	results := make([][]T, len(chunks))
+	runMergeTree(len(chunks), ops.trace, func(i, _ int) {
+		c := data[chunks[i].start:chunks[i].end]
+		ops.sort(c)
+		results[i] = c
+	}, func(lo, mid, _, _ int) {
+		ops.trace.alloc(int64(len(results[lo])+len(results[mid])) * int64(unsafe.Sizeof(*new(T))))
+		results[lo] = ops.merge(results[lo], results[mid])
+	})
+
+	if len(chunks) > 1 {
+		copy(data, results[0])
+	}
+}
+
+// mergeInto merges the sorted slices a and b into dst, which must have room for both.
+// Elements of a are written first when they compare equal, so the merge is stable.
+func mergeInto[T any](dst, a, b []T, less func(a, b T) bool) {
+	i, j, k := 0, 0, 0
+	for i < len(a) && j < len(b) {
+		if less(b[j], a[i]) {
+			dst[k] = b[j]
+			j++
+		} else {
+			dst[k] = a[i]
+			i++
+		}
+		k++
+	}
+	k += copy(dst[k:], a[i:])
+	copy(dst[k:], b[j:])
+}
+
+// pingPongTree sorts the chunks with leaf and merges them with runMergeTree, alternating
+// between two buffers of the same length. Nodes at even depths, the root included, are written
+// to a and nodes at odd depths to b, so the children of a node are always in the other buffer.
+// leaf must leave the sorted chunk i in dst, which is either a or b.
+func pingPongTree[T any](a, b []T, chunks []chunk, ops *typeOps[T], leaf func(i int, dst []T)) {
+	at := func(depth int) []T {
+		if depth%2 == 0 {
+			return a
+		}
+		return b
+	}
+	runMergeTree(len(chunks), ops.trace, func(i, depth int) {
+		leaf(i, at(depth))
+	}, func(lo, mid, hi, depth int) {
+		src, dst := at(depth+1), at(depth)
+		start, split, end := chunks[lo].start, chunks[mid].start, chunks[hi-1].end
+		mergeInto(dst[start:end], src[start:split], src[split:end], ops.less)
+	})
+}
+
+// parallelCopy copies src into dst using up to cores() goroutines.
+func parallelCopy[T any](dst, src []T) {
+	chunks := splitChunks(len(src))
+	runTasks(len(chunks), func(i int) {
+		copy(dst[chunks[i].start:chunks[i].end], src[chunks[i].start:chunks[i].end])
+	})
+}
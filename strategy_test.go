@@ -0,0 +1,430 @@
+package parsort
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"testing"
+)
+
+var strategies = []Strategy{PairwiseMerge, PingPongMerge, ChunkCopyMerge, KWayMerge, Samplesort, Radix, InPlace, Sequential, Auto}
+
+func withCoreCount(t testing.TB, n int) {
+	prev := CoreCount
+	CoreCount = n
+	t.Cleanup(func() {
+		CoreCount = prev
+	})
+}
+
+func TestStrategy_String(t *testing.T) {
+	if PairwiseMerge.String() != "PairwiseMerge" || InPlace.String() != "InPlace" {
+		t.Errorf("unexpected strategy names: %v, %v", PairwiseMerge, InPlace)
+	}
+	if Strategy(99).String() != "Strategy(99)" {
+		t.Errorf("unexpected name for unknown strategy: %v", Strategy(99))
+	}
+}
+
+func TestStrategy_RadixFallback(t *testing.T) {
+	if stringOps.resolve(Radix) != PairwiseMerge {
+		t.Errorf("expected Radix to fall back to PairwiseMerge for strings")
+	}
+	if intOps.resolve(Radix) != Radix {
+		t.Errorf("expected Radix to apply to ints")
+	}
+}
+
+func TestNewSorter_UsesDefaultStrategy(t *testing.T) {
+	prev := DefaultStrategy
+	DefaultStrategy = KWayMerge
+	defer func() { DefaultStrategy = prev }()
+
+	if s := NewSorter().Strategy(); s != KWayMerge {
+		t.Errorf("expected KWayMerge, got %v", s)
+	}
+}
+
+func TestStrategies_Int(t *testing.T) {
+	for _, cores := range []int{1, 4, 7} {
+		withCoreCount(t, cores)
+		for _, st := range strategies {
+			s := NewSorter().SetStrategy(st)
+
+			data := genInts(50000)
+			expected := append([]int(nil), data...)
+			sort.Ints(expected)
+			s.IntAsc(data)
+			if !intSlicesEqual(data, expected) {
+				t.Errorf("%v with %d cores: ascending result incorrect", st, cores)
+			}
+
+			data = genInts(50000)
+			expected = append([]int(nil), data...)
+			sort.Sort(sort.Reverse(sort.IntSlice(expected)))
+			s.IntDesc(data)
+			if !intSlicesEqual(data, expected) {
+				t.Errorf("%v with %d cores: descending result incorrect", st, cores)
+			}
+		}
+	}
+}
+
+func TestStrategies_IntEdgeCases(t *testing.T) {
+	withCoreCount(t, 6)
+	n := 30000
+	inputs := map[string]func() []int{
+		"AllEqual": func() []int {
+			a := make([]int, n)
+			for i := range a {
+				a[i] = 7
+			}
+			return a
+		},
+		"AlreadySorted": func() []int {
+			a := make([]int, n)
+			for i := range a {
+				a[i] = i
+			}
+			return a
+		},
+		"ReverseSorted": func() []int {
+			a := make([]int, n)
+			for i := range a {
+				a[i] = n - i
+			}
+			return a
+		},
+		"FewDistinct": func() []int {
+			a := genInts(n)
+			for i := range a {
+				a[i] %= 3
+			}
+			return a
+		},
+		"Negative": func() []int {
+			a := genInts(n)
+			for i := range a {
+				if i%2 == 0 {
+					a[i] = -a[i]
+				}
+			}
+			return a
+		},
+	}
+	for name, gen := range inputs {
+		for _, st := range strategies {
+			data := gen()
+			expected := append([]int(nil), data...)
+			sort.Ints(expected)
+			NewSorter().SetStrategy(st).IntAsc(data)
+			if !intSlicesEqual(data, expected) {
+				t.Errorf("%v on %s: result incorrect", st, name)
+			}
+		}
+	}
+}
+
+func TestStrategies_Int8(t *testing.T) {
+	withCoreCount(t, 5)
+	for _, st := range strategies {
+		data := genInt8s(50000)
+		expected := append([]int8(nil), data...)
+		sort.Slice(expected, func(i, j int) bool { return expected[i] < expected[j] })
+		NewSorter().SetStrategy(st).Int8Asc(data)
+		if !int8SlicesEqual(data, expected) {
+			t.Errorf("%v: result incorrect", st)
+		}
+	}
+}
+
+func TestStrategies_Int16(t *testing.T) {
+	withCoreCount(t, 5)
+	for _, st := range strategies {
+		data := genInt16s(50000)
+		expected := append([]int16(nil), data...)
+		sort.Slice(expected, func(i, j int) bool { return expected[i] < expected[j] })
+		NewSorter().SetStrategy(st).Int16Asc(data)
+		if !int16SlicesEqual(data, expected) {
+			t.Errorf("%v: result incorrect", st)
+		}
+	}
+}
+
+func TestStrategies_Int32(t *testing.T) {
+	withCoreCount(t, 5)
+	for _, st := range strategies {
+		data := genInt32s(50000)
+		for i := range data {
+			if i%3 == 0 {
+				data[i] = -data[i]
+			}
+		}
+		expected := append([]int32(nil), data...)
+		sort.Slice(expected, func(i, j int) bool { return expected[i] < expected[j] })
+		NewSorter().SetStrategy(st).Int32Asc(data)
+		if !int32SlicesEqual(data, expected) {
+			t.Errorf("%v: result incorrect", st)
+		}
+	}
+}
+
+func TestStrategies_Int64(t *testing.T) {
+	withCoreCount(t, 5)
+	for _, st := range strategies {
+		data := genInt64s(50000)
+		for i := range data {
+			if i%3 == 0 {
+				data[i] = -data[i]
+			}
+		}
+		expected := append([]int64(nil), data...)
+		sort.Slice(expected, func(i, j int) bool { return expected[i] < expected[j] })
+		NewSorter().SetStrategy(st).Int64Desc(data)
+		sort.Slice(expected, func(i, j int) bool { return expected[i] > expected[j] })
+		if !int64SlicesEqual(data, expected) {
+			t.Errorf("%v: result incorrect", st)
+		}
+	}
+}
+
+func TestStrategies_Uint(t *testing.T) {
+	withCoreCount(t, 5)
+	for _, st := range strategies {
+		data := genUints(50000)
+		expected := append([]uint(nil), data...)
+		sort.Slice(expected, func(i, j int) bool { return expected[i] < expected[j] })
+		NewSorter().SetStrategy(st).UintAsc(data)
+		if !uintSlicesEqual(data, expected) {
+			t.Errorf("%v: result incorrect", st)
+		}
+	}
+}
+
+func TestStrategies_Uint8(t *testing.T) {
+	withCoreCount(t, 5)
+	for _, st := range strategies {
+		data := genUint8s(50000)
+		expected := append([]uint8(nil), data...)
+		sort.Slice(expected, func(i, j int) bool { return expected[i] < expected[j] })
+		NewSorter().SetStrategy(st).Uint8Asc(data)
+		if !uint8SlicesEqual(data, expected) {
+			t.Errorf("%v: result incorrect", st)
+		}
+	}
+}
+
+func TestStrategies_Uint16(t *testing.T) {
+	withCoreCount(t, 5)
+	for _, st := range strategies {
+		data := genUint16s(50000)
+		expected := append([]uint16(nil), data...)
+		sort.Slice(expected, func(i, j int) bool { return expected[i] < expected[j] })
+		NewSorter().SetStrategy(st).Uint16Asc(data)
+		if !uint16SlicesEqual(data, expected) {
+			t.Errorf("%v: result incorrect", st)
+		}
+	}
+}
+
+func TestStrategies_Uint32(t *testing.T) {
+	withCoreCount(t, 5)
+	for _, st := range strategies {
+		data := genUint32s(50000)
+		expected := append([]uint32(nil), data...)
+		sort.Slice(expected, func(i, j int) bool { return expected[i] < expected[j] })
+		NewSorter().SetStrategy(st).Uint32Asc(data)
+		if !uint32SlicesEqual(data, expected) {
+			t.Errorf("%v: result incorrect", st)
+		}
+	}
+}
+
+func TestStrategies_Uint64(t *testing.T) {
+	withCoreCount(t, 5)
+	for _, st := range strategies {
+		data := genUint64s(50000)
+		expected := append([]uint64(nil), data...)
+		sort.Slice(expected, func(i, j int) bool { return expected[i] < expected[j] })
+		NewSorter().SetStrategy(st).Uint64Asc(data)
+		if !uint64SlicesEqual(data, expected) {
+			t.Errorf("%v: result incorrect", st)
+		}
+	}
+}
+
+func TestStrategies_Float32(t *testing.T) {
+	withCoreCount(t, 5)
+	for _, st := range strategies {
+		data := genFloat32s(50000)
+		for i := range data {
+			if i%3 == 0 {
+				data[i] = -data[i] * 1e6
+			}
+		}
+		data[10] = float32(math.Inf(-1))
+		data[20] = float32(math.Inf(1))
+		expected := append([]float32(nil), data...)
+		sort.Slice(expected, func(i, j int) bool { return expected[i] < expected[j] })
+		NewSorter().SetStrategy(st).Float32Asc(data)
+		for i := range data {
+			if data[i] != expected[i] {
+				t.Errorf("%v: result incorrect at %d", st, i)
+				break
+			}
+		}
+	}
+}
+
+func TestStrategies_Float64(t *testing.T) {
+	withCoreCount(t, 5)
+	for _, st := range strategies {
+		data := genFloats(50000)
+		for i := range data {
+			if i%3 == 0 {
+				data[i] = -data[i] * 1e12
+			}
+		}
+		data[10] = math.Inf(1)
+		data[30000] = math.Inf(-1)
+		expected := append([]float64(nil), data...)
+		sort.Float64s(expected)
+		NewSorter().SetStrategy(st).Float64Asc(data)
+		if !floatSlicesEqual(data, expected) {
+			t.Errorf("%v: result incorrect", st)
+		}
+	}
+}
+
+func TestStrategies_String(t *testing.T) {
+	withCoreCount(t, 5)
+	for _, st := range strategies {
+		data := genStrings(50000)
+		expected := append([]string(nil), data...)
+		sort.Strings(expected)
+		NewSorter().SetStrategy(st).StringAsc(data)
+		if !stringSlicesEqual(data, expected) {
+			t.Errorf("%v: result incorrect", st)
+		}
+	}
+}
+
+func TestStrategies_Time(t *testing.T) {
+	withCoreCount(t, 5)
+	for _, st := range strategies {
+		data := genTimes(50000)
+		expected := append(data[:0:0], data...)
+		sort.Slice(expected, func(i, j int) bool { return expected[i].Before(expected[j]) })
+		NewSorter().SetStrategy(st).TimeAsc(data)
+		if !timeSlicesEqual(data, expected) {
+			t.Errorf("%v: result incorrect", st)
+		}
+	}
+}
+
+func TestStrategies_Struct(t *testing.T) {
+	withCoreCount(t, 5)
+	for _, st := range strategies {
+		s := NewSorter().SetStrategy(st)
+
+		data := genPeople(50000)
+		StructAscWith(s, data, func(a, b person) bool { return a.Age < b.Age })
+		if !isSortedAsc(data) {
+			t.Errorf("%v: StructAscWith failed to sort correctly", st)
+		}
+
+		data = genPeople(50000)
+		StructDescWith(s, data, func(a, b person) bool { return a.Age < b.Age })
+		if !isSortedDesc(data) {
+			t.Errorf("%v: StructDescWith failed to sort correctly", st)
+		}
+	}
+}
+
+func TestStrategies_StructStable(t *testing.T) {
+	type indexed struct {
+		Key   int
+		Index int
+	}
+	withCoreCount(t, 5)
+	for _, st := range strategies {
+		data := make([]indexed, 50000)
+		for i := range data {
+			data[i] = indexed{Key: genInts(1)[0] % 50, Index: i}
+		}
+		StructAscStableWith(NewSorter().SetStrategy(st), data, func(a, b indexed) bool { return a.Key < b.Key })
+		for i := 1; i < len(data); i++ {
+			if data[i-1].Key > data[i].Key || (data[i-1].Key == data[i].Key && data[i-1].Index > data[i].Index) {
+				t.Errorf("%v: stable sort broke order at %d", st, i)
+				break
+			}
+		}
+	}
+}
+
+func TestStrategies_TinyInputs(t *testing.T) {
+	withCoreCount(t, 4)
+	prevInt, prevStruct := IntMinParallelSize, StructMinParallelSize
+	IntMinParallelSize, StructMinParallelSize = 0, 0
+	defer func() { IntMinParallelSize, StructMinParallelSize = prevInt, prevStruct }()
+
+	less := func(a, b person) bool { return a.Age < b.Age }
+	for _, st := range strategies {
+		s := NewSorter().SetStrategy(st)
+		for _, n := range []int{0, 1} {
+			if err := s.IntAsc(make([]int, n)); err != nil {
+				t.Errorf("%v: %d ints: %v", st, n, err)
+			}
+			if _, err := s.IntSorted(make([]int, n)); err != nil {
+				t.Errorf("%v: %d sorted ints: %v", st, n, err)
+			}
+			if err := StructAscWith(s, make([]person, n), less); err != nil {
+				t.Errorf("%v: %d structs: %v", st, n, err)
+			}
+			if err := StructAscStableWith(s, make([]person, n), less); err != nil {
+				t.Errorf("%v: %d stable structs: %v", st, n, err)
+			}
+			if d := s.LastDecision(); d.Strategy != Sequential {
+				t.Errorf("%v: expected %d elements to be sorted sequentially, got %+v", st, n, d)
+			}
+		}
+	}
+}
+
+func BenchmarkStrategies_Int(b *testing.B) {
+	for _, st := range strategies {
+		for _, size := range testSizes {
+			b.Run(st.String()+"_Int_"+strconv.Itoa(size), func(b *testing.B) {
+				s := NewSorter().SetStrategy(st)
+				data := genInts(size)
+				b.ReportAllocs()
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					tmp := make([]int, len(data))
+					copy(tmp, data)
+					s.IntAsc(tmp)
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkStrategies_Struct(b *testing.B) {
+	for _, st := range strategies {
+		for _, size := range testSizes {
+			b.Run(st.String()+"_Struct_"+strconv.Itoa(size), func(b *testing.B) {
+				s := NewSorter().SetStrategy(st)
+				data := genPeople(size)
+				b.ReportAllocs()
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					tmp := make([]person, len(data))
+					copy(tmp, data)
+					StructAscWith(s, tmp, func(a, b person) bool {
+						return a.Age < b.Age
+					})
+				}
+			})
+		}
+	}
+}
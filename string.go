@@ -2,72 +2,99 @@ package parsort
 
 import (
 	"sort"
-	"sync"
 )
 
 func StringAsc(data []string) {
-	stringSort(data, false)
+	stringSort(data, false, defaultSorter())
 }
 
 func StringDesc(data []string) {
-	stringSort(data, true)
+	stringSort(data, true, defaultSorter())
 }
 
-func stringSort(data []string, reverse bool) {
-	n := len(data)
-	if n < StringMinParallelSize {
-		sort.Strings(data)
-		if reverse {
-			stringReverse(data)
-		}
-		return
-	}
+// StringAsc sorts data in ascending order using the Sorter's configuration.
+// It returns an error when the Sorter's memory budget can't be met, leaving data untouched,
+// or a *PanicError when sorting panics.
+func (x *Sorter) StringAsc(data []string) error {
+	return stringSort(data, false, x)
+}
 
-	chunkSize := (n + CoreCount - 1) / CoreCount
+// StringDesc sorts data in descending order using the Sorter's configuration.
+// It returns an error when the Sorter's memory budget can't be met, leaving data untouched,
+// or a *PanicError when sorting panics.
+func (x *Sorter) StringDesc(data []string) error {
+	return stringSort(data, true, x)
+}
 
-	chunks := make([][]string, 0, CoreCount)
-	for i := 0; i < n; i += chunkSize {
-		end := i + chunkSize
-		if end > n {
-			end = n
-		}
-		chunks = append(chunks, data[i:end])
-	}
+// StringAscAsync starts sorting data in ascending order in the background, see Handle for when data may be used.
+func StringAscAsync(data []string) *Handle {
+	return async(func() error { return stringSort(data, false, returningSorter()) })
+}
 
-	var wg sync.WaitGroup
-	for _, chunk := range chunks {
-		wg.Add(1)
-		go func(c []string) {
-			defer wg.Done()
-			sort.Strings(c)
-		}(chunk)
-	}
-	wg.Wait()
-
-	for len(chunks) > 1 {
-		mergedCount := (len(chunks) + 1) / 2
-		merged := make([][]string, mergedCount)
-
-		var mWg sync.WaitGroup
-		for i := 0; i < len(chunks); i += 2 {
-			if i+1 == len(chunks) {
-				merged[i/2] = chunks[i]
-				continue
-			}
-			mWg.Add(1)
-			go func(i int) {
-				defer mWg.Done()
-				merged[i/2] = stringMergeSorted(chunks[i], chunks[i+1])
-			}(i)
-		}
-		mWg.Wait()
-		chunks = merged
-	}
+// StringDescAsync starts sorting data in descending order in the background, see Handle for when data may be used.
+func StringDescAsync(data []string) *Handle {
+	return async(func() error { return stringSort(data, true, returningSorter()) })
+}
+
+// StringAscAsync is StringAscAsync using the Sorter's configuration, cancelled with the context of WithContext.
+func (x *Sorter) StringAscAsync(data []string) *Handle {
+	return async(func() error { return stringSort(data, false, x) })
+}
+
+// StringDescAsync is StringDescAsync using the Sorter's configuration, cancelled with the context of WithContext.
+func (x *Sorter) StringDescAsync(data []string) *Handle {
+	return async(func() error { return stringSort(data, true, x) })
+}
 
-	copy(data, chunks[0])
-	if reverse {
-		stringReverse(data)
+// StringSorted returns a copy of src sorted in ascending order, leaving src untouched.
+func StringSorted(src []string) []string {
+	dst := make([]string, len(src))
+	stringSortInto(dst, src, defaultSorter())
+	return dst
+}
+
+// StringSortedInto writes src sorted in ascending order to dst, leaving src untouched.
+// dst must have the length of src and must either be src or not overlap it.
+func StringSortedInto(dst, src []string) {
+	stringSortInto(dst, src, defaultSorter())
+}
+
+// StringSorted returns a copy of src sorted in ascending order using the Sorter's configuration.
+// It returns the errors of StringAsc, leaving src untouched.
+func (x *Sorter) StringSorted(src []string) ([]string, error) {
+	dst := make([]string, len(src))
+	if err := stringSortInto(dst, src, x); err != nil {
+		return nil, err
 	}
+	return dst, nil
+}
+
+// StringSortedInto writes src sorted in ascending order to dst using the Sorter's configuration.
+// It returns the errors of StringAsc, leaving src untouched.
+func (x *Sorter) StringSortedInto(dst, src []string) error {
+	return stringSortInto(dst, src, x)
+}
+
+// StringLazy sorts the chunks of data in parallel and returns an Iterator over its values in
+// ascending order, merging the chunks only as far as values are requested.
+func StringLazy(data []string) *Iterator[string] {
+	return lazySort(data, StringMinParallelSize, &stringOps)
+}
+
+var stringOps = typeOps[string]{
+	less:    func(a, b string) bool { return a < b },
+	sort:    sort.Strings,
+	merge:   stringMergeSorted,
+	prefix:  commonPrefix,
+	reverse: stringReverse,
+}
+
+func stringSort(data []string, reverse bool, s *Sorter) error {
+	return sortSlice(data, reverse, s, StringMinParallelSize, &stringOps)
+}
+
+func stringSortInto(dst, src []string, s *Sorter) error {
+	return sortSliceInto(dst, src, s, StringMinParallelSize, &stringOps)
 }
 
 func stringMergeSorted(a, b []string) []string {
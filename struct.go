@@ -2,203 +2,169 @@ package parsort
 
 import (
 	"sort"
-	"sync"
+	"unsafe"
 )
 
 type chunk struct{ start, end int }
 
 // structSortUnstable sorts a slice using parallel unstable sorting and in-place merging.
-func structSortUnstable[T any](data []T, less func(a, b T) bool) {
-	n := len(data)
-	if n <= 1 {
-		return
-	}
-
-	if n < StructMinParallelSize {
-		sort.Slice(data, func(i, j int) bool {
-			return less(data[i], data[j])
-		})
-		return
-	}
-
-	chunkSize := (n + CoreCount - 1) / CoreCount
-	chunks := make([]chunk, 0, CoreCount)
-
-	for i := 0; i < n; i += chunkSize {
-		end := i + chunkSize
-		if end > n {
-			end = n
-		}
-		chunks = append(chunks, chunk{i, end})
-	}
-
-	var wg sync.WaitGroup
-	for _, ch := range chunks {
-		wg.Add(1)
-		go func(start, end int) {
-			defer wg.Done()
-			sort.Slice(data[start:end], func(i, j int) bool {
-				return less(data[start+i], data[start+j])
-			})
-		}(ch.start, ch.end)
-	}
-	wg.Wait()
-
-	dst := make([]T, n)
-	src := data
-
-	for len(chunks) > 1 {
-		merged := make([]chunk, 0, (len(chunks)+1)/2)
-		var mWg sync.WaitGroup
-
-		for i := 0; i < len(chunks); i += 2 {
-			if i+1 == len(chunks) {
-				copy(dst[chunks[i].start:chunks[i].end], src[chunks[i].start:chunks[i].end])
-				merged = append(merged, chunks[i])
-				continue
-			}
-
-			a, b := chunks[i], chunks[i+1]
-			outStart := a.start
-			outEnd := b.end
-			merged = append(merged, chunk{outStart, outEnd})
-
-			mWg.Add(1)
-			go func(a, b chunk) {
-				defer mWg.Done()
-				i, j, k := a.start, b.start, a.start
-				for i < a.end && j < b.end {
-					if less(src[i], src[j]) {
-						dst[k] = src[i]
-						i++
-					} else {
-						dst[k] = src[j]
-						j++
-					}
-					k++
-				}
-				copy(dst[k:], src[i:a.end])
-				copy(dst[k+(a.end-i):], src[j:b.end])
-			}(a, b)
-		}
-		mWg.Wait()
-		src, dst = dst, src
-		chunks = merged
-	}
-
-	if &src[0] != &data[0] {
-		copy(data, src)
+// Large elements are sorted indirectly, see StructIndirectSize.
+func structSortUnstable[T any](data []T, less func(a, b T) bool, s *Sorter) error {
+	if s.indirect(int(unsafe.Sizeof(*new(T))), len(data)) {
+		return sortIndirect(data, less, false, s)
 	}
+	return sortSlice(data, false, s, StructMinParallelSize, structOps(less, false))
 }
 
 // structSortStable sorts a slice using parallel stable sorting and in-place merging.
-func structSortStable[T any](data []T, less func(a, b T) bool) {
-	n := len(data)
-	if n <= 1 {
-		return
+// Large elements are sorted indirectly, see StructIndirectSize.
+func structSortStable[T any](data []T, less func(a, b T) bool, s *Sorter) error {
+	if s.indirect(int(unsafe.Sizeof(*new(T))), len(data)) {
+		return sortIndirect(data, less, true, s)
 	}
+	return sortSlice(data, false, s, StructMinParallelSize, structOps(less, true))
+}
 
-	if n < StructMinParallelSize {
-		sort.SliceStable(data, func(i, j int) bool {
-			return less(data[i], data[j])
-		})
-		return
+// structOps returns the strategy building blocks for sorting structs with less.
+func structOps[T any](less func(a, b T) bool, stable bool) *typeOps[T] {
+	ops := &typeOps[T]{
+		less:     less,
+		stable:   stable,
+		userLess: true,
 	}
-
-	chunkSize := (n + CoreCount - 1) / CoreCount
-	chunks := make([]chunk, 0, CoreCount)
-
-	for i := 0; i < n; i += chunkSize {
-		end := i + chunkSize
-		if end > n {
-			end = n
+	if stable {
+		ops.sort = func(c []T) {
+			sort.SliceStable(c, func(i, j int) bool {
+				return less(c[i], c[j])
+			})
 		}
-		chunks = append(chunks, chunk{i, end})
-	}
-
-	var wg sync.WaitGroup
-	for _, ch := range chunks {
-		wg.Add(1)
-		go func(start, end int) {
-			defer wg.Done()
-			sort.SliceStable(data[start:end], func(i, j int) bool {
-				return less(data[start+i], data[start+j])
+	} else {
+		ops.sort = func(c []T) {
+			sort.Slice(c, func(i, j int) bool {
+				return less(c[i], c[j])
 			})
-		}(ch.start, ch.end)
-	}
-	wg.Wait()
-
-	dst := make([]T, n)
-	src := data
-
-	for len(chunks) > 1 {
-		merged := make([]chunk, 0, (len(chunks)+1)/2)
-		var mWg sync.WaitGroup
-
-		for i := 0; i < len(chunks); i += 2 {
-			if i+1 == len(chunks) {
-				copy(dst[chunks[i].start:chunks[i].end], src[chunks[i].start:chunks[i].end])
-				merged = append(merged, chunks[i])
-				continue
-			}
-
-			a, b := chunks[i], chunks[i+1]
-			outStart := a.start
-			outEnd := b.end
-			merged = append(merged, chunk{outStart, outEnd})
-
-			mWg.Add(1)
-			go func(a, b chunk) {
-				defer mWg.Done()
-				i, j, k := a.start, b.start, a.start
-				for i < a.end && j < b.end {
-					switch {
-					case less(src[i], src[j]):
-						dst[k] = src[i]
-						i++
-					case less(src[j], src[i]):
-						dst[k] = src[j]
-						j++
-					default:
-						dst[k] = src[i] // preserve left-side element
-						i++
-					}
-					k++
-				}
-				copy(dst[k:], src[i:a.end])
-				copy(dst[k+(a.end-i):], src[j:b.end])
-			}(a, b)
 		}
-		mWg.Wait()
-		src, dst = dst, src
-		chunks = merged
-	}
-
-	if &src[0] != &data[0] {
-		copy(data, src)
 	}
+	return ops
 }
 
 // StructAsc sorts a slice of structs in ascending order using unstable sort.
+// A panic in less is raised again on the calling goroutine, as a *PanicError when it
+// happened in one of the sort's goroutines.
 func StructAsc[T any](data []T, less func(a, b T) bool) {
-	structSortUnstable(data, less)
+	structSortUnstable(data, less, defaultSorter())
+}
+
+// StructSorted returns a copy of src sorted in ascending order using unstable sort, leaving src untouched.
+func StructSorted[T any](src []T, less func(a, b T) bool) []T {
+	dst := make([]T, len(src))
+	sortSliceInto(dst, src, defaultSorter(), StructMinParallelSize, structOps(less, false))
+	return dst
+}
+
+// StructSortedInto writes src sorted in ascending order using unstable sort to dst, leaving src untouched.
+// dst must have the length of src and must either be src or not overlap it.
+func StructSortedInto[T any](dst, src []T, less func(a, b T) bool) {
+	sortSliceInto(dst, src, defaultSorter(), StructMinParallelSize, structOps(less, false))
+}
+
+// StructLazy sorts the chunks of data in parallel and returns an Iterator over its values in
+// ascending order of less, merging the chunks only as far as values are requested.
+// Equal values are returned in an unspecified order.
+func StructLazy[T any](data []T, less func(a, b T) bool) *Iterator[T] {
+	return lazySort(data, StructMinParallelSize, structOps(less, false))
 }
 
 // StructDesc sorts a slice of structs in descending order using unstable sort.
 func StructDesc[T any](data []T, less func(a, b T) bool) {
 	structSortUnstable(data, func(a, b T) bool {
 		return less(b, a)
-	})
+	}, defaultSorter())
 }
 
 // StructAscStable sorts a slice of structs in ascending order using stable sort.
 func StructAscStable[T any](data []T, less func(a, b T) bool) {
-	structSortStable(data, less)
+	structSortStable(data, less, defaultSorter())
 }
 
 // StructDescStable sorts a slice of structs in descending order using stable sort.
 func StructDescStable[T any](data []T, less func(a, b T) bool) {
 	structSortStable(data, func(a, b T) bool {
 		return less(b, a)
-	})
+	}, defaultSorter())
+}
+
+// StructAscWith is StructAsc using the configuration of s.
+// It returns an error when the memory budget of s can't be met, leaving data untouched,
+// or a *PanicError when less panics.
+func StructAscWith[T any](s *Sorter, data []T, less func(a, b T) bool) error {
+	return structSortUnstable(data, less, s)
+}
+
+// StructDescWith is StructDesc using the configuration of s.
+func StructDescWith[T any](s *Sorter, data []T, less func(a, b T) bool) error {
+	return structSortUnstable(data, func(a, b T) bool {
+		return less(b, a)
+	}, s)
+}
+
+// StructAscStableWith is StructAscStable using the configuration of s.
+func StructAscStableWith[T any](s *Sorter, data []T, less func(a, b T) bool) error {
+	return structSortStable(data, less, s)
+}
+
+// StructDescStableWith is StructDescStable using the configuration of s.
+func StructDescStableWith[T any](s *Sorter, data []T, less func(a, b T) bool) error {
+	return structSortStable(data, func(a, b T) bool {
+		return less(b, a)
+	}, s)
+}
+
+// StructSortedWith is StructSorted using the configuration of s.
+// It returns the errors of StructAscWith, leaving src untouched.
+func StructSortedWith[T any](s *Sorter, src []T, less func(a, b T) bool) ([]T, error) {
+	dst := make([]T, len(src))
+	if err := sortSliceInto(dst, src, s, StructMinParallelSize, structOps(less, false)); err != nil {
+		return nil, err
+	}
+	return dst, nil
+}
+
+// StructSortedIntoWith is StructSortedInto using the configuration of s.
+func StructSortedIntoWith[T any](s *Sorter, dst, src []T, less func(a, b T) bool) error {
+	return sortSliceInto(dst, src, s, StructMinParallelSize, structOps(less, false))
+}
+
+// StructAscAsync starts StructAsc in the background, see Handle for when data may be used.
+func StructAscAsync[T any](data []T, less func(a, b T) bool) *Handle {
+	return async(func() error { return structSortUnstable(data, less, returningSorter()) })
+}
+
+// StructAscStableAsync starts StructAscStable in the background, see Handle for when data may be used.
+func StructAscStableAsync[T any](data []T, less func(a, b T) bool) *Handle {
+	return async(func() error { return structSortStable(data, less, returningSorter()) })
+}
+
+// StructAscAsyncWith is StructAscAsync using the configuration of s, cancelled with the context of WithContext.
+func StructAscAsyncWith[T any](s *Sorter, data []T, less func(a, b T) bool) *Handle {
+	return async(func() error { return structSortUnstable(data, less, s) })
+}
+
+// StructAscStableAsyncWith is StructAscStableAsync using the configuration of s.
+func StructAscStableAsyncWith[T any](s *Sorter, data []T, less func(a, b T) bool) *Handle {
+	return async(func() error { return structSortStable(data, less, s) })
+}
+
+// StructAscErr sorts a slice of structs in ascending order of cmp, which returns a negative number
+// when a < b, a positive one when a > b and 0 otherwise, using unstable sort. The first error
+// returned by cmp stops every goroutine of the sort and is returned, leaving data untouched.
+// A panic in cmp is returned as a *PanicError, also leaving data untouched.
+// data is sorted out of place, which takes one extra copy of data on top of the sort's own memory.
+func StructAscErr[T any](data []T, cmp func(a, b T) (int, error)) error {
+	return structSortErr(data, cmp, returningSorter())
+}
+
+// StructAscErrWith is StructAscErr using the configuration of s.
+func StructAscErrWith[T any](s *Sorter, data []T, cmp func(a, b T) (int, error)) error {
+	return structSortErr(data, cmp, s)
 }
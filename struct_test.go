@@ -89,7 +89,7 @@ func BenchmarkSortStruct_Arbitrary(b *testing.B) {
 				copy(tmp, original)
 				structSortUnstable(tmp, func(a, b person) bool {
 					return a.Age < b.Age
-				})
+				}, defaultSorter())
 			}
 		})
 	}
@@ -0,0 +1,269 @@
+package parsort
+
+import (
+	"fmt"
+	"time"
+)
+
+// Table sorts parallel columns, a struct-of-arrays table, in unison. Columns are registered with
+// AddColumn or AddColumnFunc and the sort keys declared with OrderBy. Sorting compares rows with
+// the comparison of every key column's type, as used by the package level functions, then
+// reorders all columns in parallel. The sort is stable: rows with equal keys keep their order.
+//
+// Example:
+//
+//	t := parsort.NewTable()
+//	parsort.AddColumn(t, "id", ids)
+//	parsort.AddColumn(t, "price", prices)
+//	parsort.AddColumn(t, "name", names)
+//	err := t.OrderBy(parsort.Desc("price"), parsort.Asc("name")).Sort()
+type Table struct {
+	columns []column
+	keys    []SortKey
+}
+
+// SortKey is a column a Table is sorted by, and its direction.
+type SortKey struct {
+	Column     string
+	Descending bool
+}
+
+// Asc returns the key sorting a Table by column in ascending order.
+func Asc(column string) SortKey {
+	return SortKey{Column: column}
+}
+
+// Desc returns the key sorting a Table by column in descending order.
+func Desc(column string) SortKey {
+	return SortKey{Column: column, Descending: true}
+}
+
+// column is a registered column of a Table, whatever its element type.
+type column interface {
+	name() string
+	length() int
+	// comparable reports whether the column can be a sort key.
+	comparable() bool
+	// compare returns -1, 0 or 1 as row i is before, equivalent to or after row j.
+	compare(i, j int) int
+	// alloc allocates the reordered column, release drops it.
+	alloc()
+	release()
+	// gather sets the rows [lo, hi) of the reordered column to the rows perm[lo:hi].
+	gather(perm []int, lo, hi int)
+	// commit copies the rows [lo, hi) of the reordered column back into the column.
+	commit(lo, hi int)
+	// hash returns the multisetHash of the column.
+	hash() uint64
+}
+
+type typedColumn[T any] struct {
+	label     string
+	data      []T
+	less      func(a, b T) bool
+	reordered []T
+}
+
+func (x *typedColumn[T]) name() string     { return x.label }
+func (x *typedColumn[T]) length() int      { return len(x.data) }
+func (x *typedColumn[T]) comparable() bool { return x.less != nil }
+
+func (x *typedColumn[T]) compare(i, j int) int {
+	switch {
+	case x.less(x.data[i], x.data[j]):
+		return -1
+	case x.less(x.data[j], x.data[i]):
+		return 1
+	}
+	return 0
+}
+
+func (x *typedColumn[T]) alloc()   { x.reordered = make([]T, len(x.data)) }
+func (x *typedColumn[T]) release() { x.reordered = nil }
+
+func (x *typedColumn[T]) gather(perm []int, lo, hi int) {
+	for i := lo; i < hi; i++ {
+		x.reordered[i] = x.data[perm[i]]
+	}
+}
+
+func (x *typedColumn[T]) commit(lo, hi int) {
+	copy(x.data[lo:hi], x.reordered[lo:hi])
+}
+
+func (x *typedColumn[T]) hash() uint64 { return multisetHash(x.data) }
+
+// NewTable returns an empty Table.
+func NewTable() *Table {
+	return &Table{}
+}
+
+// AddColumn registers data as the column name of t. Columns of the types sorted by the package
+// level functions can be sort keys, other columns are only reordered.
+// Every column must have the same length, which is checked by Sort.
+func AddColumn[T any](t *Table, name string, data []T) *Table {
+	var less func(a, b T) bool
+	if ops := builtinOps[T](); ops != nil {
+		less = ops.less
+	}
+	return AddColumnFunc(t, name, data, less)
+}
+
+// AddColumnFunc registers data as the column name of t, compared with less when it is a sort key.
+func AddColumnFunc[T any](t *Table, name string, data []T, less func(a, b T) bool) *Table {
+	t.columns = append(t.columns, &typedColumn[T]{label: name, data: data, less: less})
+	return t
+}
+
+// OrderBy sets the keys t is sorted by, the first one deciding first.
+func (x *Table) OrderBy(keys ...SortKey) *Table {
+	x.keys = append([]SortKey(nil), keys...)
+	return x
+}
+
+// Sort sorts the rows of x by its keys using the package configuration. It returns an error, leaving
+// the columns untouched, when the columns have different lengths or a key names no comparable column.
+// A panic in a comparison is raised again on the calling goroutine. Reordering allocates one
+// copy of every column on top of what sorting the row indexes allocates.
+func (x *Table) Sort() error {
+	return x.sort(defaultSorter())
+}
+
+// SortWith is Sort using the configuration of s. It also returns the errors of the Sorter methods.
+func (x *Table) SortWith(s *Sorter) error {
+	return x.sort(s)
+}
+
+func (x *Table) sort(s *Sorter) error {
+	if len(x.columns) == 0 {
+		return nil
+	}
+	n := x.columns[0].length()
+	byName := make(map[string]column, len(x.columns))
+	for _, c := range x.columns {
+		if c.length() != n {
+			return fmt.Errorf("parsort: column %q has %d rows, column %q has %d", c.name(), c.length(), x.columns[0].name(), n)
+		}
+		if _, ok := byName[c.name()]; ok {
+			return fmt.Errorf("parsort: column %q registered twice", c.name())
+		}
+		byName[c.name()] = c
+	}
+	keys := make([]column, len(x.keys))
+	for i, k := range x.keys {
+		c, ok := byName[k.Column]
+		if !ok {
+			return fmt.Errorf("parsort: no column %q", k.Column)
+		}
+		if !c.comparable() {
+			return fmt.Errorf("parsort: column %q has no comparison, register it with AddColumnFunc", k.Column)
+		}
+		keys[i] = c
+	}
+	if len(keys) == 0 || n < 2 {
+		return nil
+	}
+
+	perm := make([]int, n)
+	for i := range perm {
+		perm[i] = i
+	}
+	less := func(a, b int) bool {
+		for i, c := range keys {
+			if r := c.compare(a, b); r != 0 {
+				return (r < 0) != x.keys[i].Descending
+			}
+		}
+		return false
+	}
+	var hash uint64
+	if s.verify {
+		hash = x.hash()
+	}
+	var d Decision
+	ops := structOps(less, true)
+	ops.name = tableTypeName
+	ops.decided = &d
+	if err := sortSlice(perm, false, withoutVerify(s), StructMinParallelSize, ops); err != nil {
+		return err
+	}
+	x.reorder(perm)
+	if s.verify {
+		// less compares the reordered rows now, they must be in order.
+		for i := range perm {
+			perm[i] = i
+		}
+		reportUnverified(s, tableTypeName, d, firstUnsorted(perm, less), x.hash() == hash)
+	}
+	return nil
+}
+
+// tableTypeName is the element type reported for the rows of a Table.
+const tableTypeName = "parsort.Table"
+
+// hash combines the multisetHash of every column of x.
+func (x *Table) hash() uint64 {
+	var h uint64
+	for _, c := range x.columns {
+		h = mix64(h ^ c.hash())
+	}
+	return h
+}
+
+// reorder moves row perm[i] of every column to row i, all columns and ranges in parallel.
+func (x *Table) reorder(perm []int) {
+	for _, c := range x.columns {
+		c.alloc()
+	}
+	chunks := splitChunks(len(perm))
+	tasks := len(x.columns) * len(chunks)
+	runTasks(tasks, func(i int) {
+		c, r := x.columns[i/len(chunks)], chunks[i%len(chunks)]
+		c.gather(perm, r.start, r.end)
+	})
+	runTasks(tasks, func(i int) {
+		c, r := x.columns[i/len(chunks)], chunks[i%len(chunks)]
+		c.commit(r.start, r.end)
+	})
+	for _, c := range x.columns {
+		c.release()
+	}
+}
+
+// builtinOps returns the building blocks of the package level functions for T, nil when
+// T isn't one of the types they sort.
+func builtinOps[T any]() *typeOps[T] {
+	var ops any
+	switch any(*new(T)).(type) {
+	case int:
+		ops = &intOps
+	case int8:
+		ops = &int8Ops
+	case int16:
+		ops = &int16Ops
+	case int32:
+		ops = &int32Ops
+	case int64:
+		ops = &int64Ops
+	case uint:
+		ops = &uintOps
+	case uint8:
+		ops = &uint8Ops
+	case uint16:
+		ops = &uint16Ops
+	case uint32:
+		ops = &uint32Ops
+	case uint64:
+		ops = &uint64Ops
+	case float32:
+		ops = &float32Ops
+	case float64:
+		ops = &float64Ops
+	case string:
+		ops = &stringOps
+	case time.Time:
+		ops = &timeOps
+	}
+	o, _ := ops.(*typeOps[T])
+	return o
+}
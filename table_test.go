@@ -0,0 +1,143 @@
+package parsort
+
+import (
+	"errors"
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+)
+
+type row struct {
+	id    int64
+	price float64
+	name  string
+	at    time.Time
+	extra [2]int
+}
+
+func genColumns(n int) ([]int64, []float64, []string, []time.Time, [][2]int) {
+	ids := make([]int64, n)
+	prices := make([]float64, n)
+	names := genStrings(n)
+	times := genTimes(n)
+	extras := make([][2]int, n)
+	for i := range ids {
+		ids[i] = int64(i)
+		prices[i] = float64(rand.Intn(100))
+		extras[i] = [2]int{i, -i}
+	}
+	return ids, prices, names, times, extras
+}
+
+func TestTable(t *testing.T) {
+	withCoreCount(t, 4)
+	ids, prices, names, times, extras := genColumns(50000)
+	before := make(map[int64]row, len(ids))
+	for i := range ids {
+		before[ids[i]] = row{ids[i], prices[i], names[i], times[i], extras[i]}
+	}
+
+	tab := NewTable()
+	AddColumn(tab, "id", ids)
+	AddColumn(tab, "price", prices)
+	AddColumn(tab, "name", names)
+	AddColumn(tab, "at", times)
+	AddColumn(tab, "extra", extras)
+	if err := tab.OrderBy(Desc("price"), Asc("name")).Sort(); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	for i := range ids {
+		if got := (row{ids[i], prices[i], names[i], times[i], extras[i]}); got != before[ids[i]] {
+			t.Fatalf("row %d not moved in unison: %+v, expected %+v", i, got, before[ids[i]])
+		}
+		if i == 0 {
+			continue
+		}
+		if prices[i-1] < prices[i] || (prices[i-1] == prices[i] && names[i-1] > names[i]) {
+			t.Fatalf("rows %d and %d out of order", i-1, i)
+		}
+		if prices[i-1] == prices[i] && names[i-1] == names[i] && ids[i-1] > ids[i] {
+			t.Fatalf("rows %d and %d not stable", i-1, i)
+		}
+	}
+}
+
+func TestTable_ObserveAndVerify(t *testing.T) {
+	withCoreCount(t, 4)
+	rec := &recorder{}
+	s := NewSorter().SetObserver(rec).SetVerify(true, func(err *VerifyError) {
+		t.Errorf("unexpected failure: %v", err)
+	})
+	ids, prices, names, _, _ := genColumns(50000)
+	tab := NewTable()
+	AddColumn(tab, "id", ids)
+	AddColumn(tab, "price", prices)
+	AddColumn(tab, "name", names)
+	if err := tab.OrderBy(Asc("price"), Desc("name")).SortWith(s); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(rec.events) == 0 {
+		t.Fatalf("expected events")
+	}
+	for _, e := range rec.events {
+		if e.Type != "parsort.Table" {
+			t.Fatalf("expected events about parsort.Table, got %+v", e)
+		}
+	}
+}
+
+func TestTable_Func(t *testing.T) {
+	withCoreCount(t, 4)
+	ids, _, _, times, extras := genColumns(20000)
+	tab := NewTable()
+	AddColumn(tab, "id", ids)
+	AddColumnFunc(tab, "extra", extras, func(a, b [2]int) bool { return a[1] < b[1] })
+	AddColumn(tab, "at", times)
+	if err := tab.OrderBy(Asc("extra")).SortWith(NewSorter().SetStrategy(KWayMerge)); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	for i := range ids {
+		if ids[i] != int64(len(ids)-1-i) || extras[i][0] != int(ids[i]) {
+			t.Fatalf("row %d incorrect: id %d, extra %v", i, ids[i], extras[i])
+		}
+	}
+}
+
+func TestTable_Errors(t *testing.T) {
+	ids, prices, _, _, extras := genColumns(10)
+	for _, c := range []struct {
+		tab  *Table
+		want string
+	}{
+		{AddColumn(AddColumn(NewTable(), "id", ids), "price", prices[:5]).OrderBy(Asc("id")), "has 5 rows"},
+		{AddColumn(AddColumn(NewTable(), "id", ids), "id", ids).OrderBy(Asc("id")), "registered twice"},
+		{AddColumn(NewTable(), "id", ids).OrderBy(Asc("price")), "no column"},
+		{AddColumn(NewTable(), "extra", extras).OrderBy(Asc("extra")), "no comparison"},
+	} {
+		if err := c.tab.Sort(); err == nil || !strings.Contains(err.Error(), c.want) {
+			t.Errorf("expected an error containing %q, got %v", c.want, err)
+		}
+	}
+	if ids[0] != 0 || ids[9] != 9 {
+		t.Errorf("columns modified by a failed sort")
+	}
+	if err := NewTable().Sort(); err != nil {
+		t.Errorf("empty table: %v", err)
+	}
+}
+
+func TestTable_Budget(t *testing.T) {
+	withCoreCount(t, 4)
+	ids, prices, _, _, _ := genColumns(50000)
+	tab := AddColumn(AddColumn(NewTable(), "id", ids), "price", prices).OrderBy(Asc("price"))
+	if err := tab.SortWith(NewSorter().SetMaxExtraBytes(1)); !errors.Is(err, ErrMemoryBudget) {
+		t.Errorf("expected ErrMemoryBudget, got %v", err)
+	}
+	for i := range ids {
+		if ids[i] != int64(i) {
+			t.Fatalf("columns modified by a failed sort")
+		}
+	}
+}
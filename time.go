@@ -1,78 +1,99 @@
 package parsort
 
 import (
-	"sort"
-	"sync"
 	"time"
 )
 
 func TimeAsc(data []time.Time) {
-	timeSort(data, false)
+	timeSort(data, false, defaultSorter())
 }
 
 func TimeDesc(data []time.Time) {
-	timeSort(data, true)
+	timeSort(data, true, defaultSorter())
 }
 
-func timeSort(data []time.Time, reverse bool) {
-	n := len(data)
-	if n < TimeMinParallelSize {
-		sort.Slice(data, func(i, j int) bool {
-			return data[i].Before(data[j])
-		})
-		if reverse {
-			timeReverse(data)
-		}
-		return
-	}
+// TimeAsc sorts data in ascending order using the Sorter's configuration.
+// It returns an error when the Sorter's memory budget can't be met, leaving data untouched,
+// or a *PanicError when sorting panics.
+func (x *Sorter) TimeAsc(data []time.Time) error {
+	return timeSort(data, false, x)
+}
 
-	chunkSize := (n + CoreCount - 1) / CoreCount
+// TimeDesc sorts data in descending order using the Sorter's configuration.
+// It returns an error when the Sorter's memory budget can't be met, leaving data untouched,
+// or a *PanicError when sorting panics.
+func (x *Sorter) TimeDesc(data []time.Time) error {
+	return timeSort(data, true, x)
+}
 
-	chunks := make([][]time.Time, 0, CoreCount)
-	for i := 0; i < n; i += chunkSize {
-		end := i + chunkSize
-		if end > n {
-			end = n
-		}
-		chunks = append(chunks, data[i:end])
-	}
+// TimeAscAsync starts sorting data in ascending order in the background, see Handle for when data may be used.
+func TimeAscAsync(data []time.Time) *Handle {
+	return async(func() error { return timeSort(data, false, returningSorter()) })
+}
 
-	var wg sync.WaitGroup
-	for _, chunk := range chunks {
-		wg.Add(1)
-		go func(c []time.Time) {
-			defer wg.Done()
-			sort.Slice(c, func(i, j int) bool {
-				return c[i].Before(c[j])
-			})
-		}(chunk)
-	}
-	wg.Wait()
-
-	for len(chunks) > 1 {
-		mergedCount := (len(chunks) + 1) / 2
-		merged := make([][]time.Time, mergedCount)
-
-		var mWg sync.WaitGroup
-		for i := 0; i < len(chunks); i += 2 {
-			if i+1 == len(chunks) {
-				merged[i/2] = chunks[i]
-				continue
-			}
-			mWg.Add(1)
-			go func(i int) {
-				defer mWg.Done()
-				merged[i/2] = timeMergeSorted(chunks[i], chunks[i+1])
-			}(i)
-		}
-		mWg.Wait()
-		chunks = merged
-	}
+// TimeDescAsync starts sorting data in descending order in the background, see Handle for when data may be used.
+func TimeDescAsync(data []time.Time) *Handle {
+	return async(func() error { return timeSort(data, true, returningSorter()) })
+}
+
+// TimeAscAsync is TimeAscAsync using the Sorter's configuration, cancelled with the context of WithContext.
+func (x *Sorter) TimeAscAsync(data []time.Time) *Handle {
+	return async(func() error { return timeSort(data, false, x) })
+}
+
+// TimeDescAsync is TimeDescAsync using the Sorter's configuration, cancelled with the context of WithContext.
+func (x *Sorter) TimeDescAsync(data []time.Time) *Handle {
+	return async(func() error { return timeSort(data, true, x) })
+}
 
-	copy(data, chunks[0])
-	if reverse {
-		timeReverse(data)
+// TimeSorted returns a copy of src sorted in ascending order, leaving src untouched.
+func TimeSorted(src []time.Time) []time.Time {
+	dst := make([]time.Time, len(src))
+	timeSortInto(dst, src, defaultSorter())
+	return dst
+}
+
+// TimeSortedInto writes src sorted in ascending order to dst, leaving src untouched.
+// dst must have the length of src and must either be src or not overlap it.
+func TimeSortedInto(dst, src []time.Time) {
+	timeSortInto(dst, src, defaultSorter())
+}
+
+// TimeSorted returns a copy of src sorted in ascending order using the Sorter's configuration.
+// It returns the errors of TimeAsc, leaving src untouched.
+func (x *Sorter) TimeSorted(src []time.Time) ([]time.Time, error) {
+	dst := make([]time.Time, len(src))
+	if err := timeSortInto(dst, src, x); err != nil {
+		return nil, err
 	}
+	return dst, nil
+}
+
+// TimeSortedInto writes src sorted in ascending order to dst using the Sorter's configuration.
+// It returns the errors of TimeAsc, leaving src untouched.
+func (x *Sorter) TimeSortedInto(dst, src []time.Time) error {
+	return timeSortInto(dst, src, x)
+}
+
+// TimeLazy sorts the chunks of data in parallel and returns an Iterator over its values in
+// ascending order, merging the chunks only as far as values are requested.
+func TimeLazy(data []time.Time) *Iterator[time.Time] {
+	return lazySort(data, TimeMinParallelSize, &timeOps)
+}
+
+var timeOps = typeOps[time.Time]{
+	less:    func(a, b time.Time) bool { return a.Before(b) },
+	sort:    sortTimes,
+	merge:   timeMergeSorted,
+	reverse: timeReverse,
+}
+
+func timeSort(data []time.Time, reverse bool, s *Sorter) error {
+	return sortSlice(data, reverse, s, TimeMinParallelSize, &timeOps)
+}
+
+func timeSortInto(dst, src []time.Time, s *Sorter) error {
+	return sortSliceInto(dst, src, s, TimeMinParallelSize, &timeOps)
 }
 
 func timeMergeSorted(a, b []time.Time) []time.Time {
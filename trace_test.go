@@ -0,0 +1,89 @@
+package parsort
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"runtime/pprof"
+	"runtime/trace"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestTrace_Regions(t *testing.T) {
+	withCoreCount(t, 4)
+	if trace.IsEnabled() {
+		t.Skip("execution tracer already running")
+	}
+	var buf bytes.Buffer
+	if err := trace.Start(&buf); err != nil {
+		t.Fatal(err)
+	}
+	s := NewSorter().SetStrategy(PairwiseMerge)
+	data := genInts(200000)
+	s.IntAsc(data)
+	NewSorter().SetStrategy(Radix).Uint32Asc(genUint32s(200000))
+	trace.Stop()
+
+	for _, name := range []string{"parsort", regionChunkSort, regionMerge, regionRadixPass} {
+		if !bytes.Contains(buf.Bytes(), []byte(name)) {
+			t.Errorf("expected %q in the execution trace", name)
+		}
+	}
+	if !sort.IntsAreSorted(data) {
+		t.Errorf("slice not sorted")
+	}
+}
+
+// labeledGoroutines returns the number of goroutines carrying the label key=value.
+func labeledGoroutines(t *testing.T, key, value string) int {
+	var buf bytes.Buffer
+	if err := pprof.Lookup("goroutine").WriteTo(&buf, 1); err != nil {
+		t.Fatal(err)
+	}
+	// Goroutines with identical stacks are grouped as "<count> @ <pcs>" followed by "# labels: {...}".
+	count, total := 0, 0
+	want := strconv.Quote(key) + ":" + strconv.Quote(value)
+	sc := bufio.NewScanner(&buf)
+	for sc.Scan() {
+		line := sc.Text()
+		if i := strings.Index(line, " @ "); i > 0 {
+			count, _ = strconv.Atoi(line[:i])
+		}
+		if strings.HasPrefix(line, "# labels:") && strings.Contains(line, want) {
+			total += count
+		}
+	}
+	return total
+}
+
+func TestWithContext_LabelsWorkers(t *testing.T) {
+	withCoreCount(t, 4)
+	withMaxWorkers(t, 8)
+	ctx := pprof.WithLabels(context.Background(), pprof.Labels("request", "42"))
+	s := NewSorter().SetStrategy(PingPongMerge).WithContext(ctx)
+
+	var once sync.Once
+	labeled := 0
+	data := genPeople(100000)
+	StructAscStableWith(s, data, func(a, b person) bool {
+		// The first comparison happens once all workers of the chunk sorting phase are started.
+		once.Do(func() {
+			labeled = labeledGoroutines(t, "request", "42")
+		})
+		return a.Age < b.Age
+	})
+
+	if labeled < 2 {
+		t.Errorf("expected the caller and its workers to carry the request label, %d do", labeled)
+	}
+	if !isSortedAsc(data) {
+		t.Errorf("slice not sorted")
+	}
+	if s.Strategy() != PingPongMerge {
+		t.Errorf("expected WithContext to keep the configuration")
+	}
+}
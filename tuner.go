@@ -72,9 +72,7 @@ func TuneSpecific(runs int, startSize int, increment int, deltaThreshold float64
 			func() {
 				data := make([]int8, len(sampleData))
 				copy(data, sampleData)
-				sort.Slice(data, func(i, j int) bool {
-					return data[i] < data[j]
-				})
+				sortOrdered(data)
 			},
 			func() {
 				data := make([]int8, len(sampleData))
@@ -108,9 +106,7 @@ func TuneSpecific(runs int, startSize int, increment int, deltaThreshold float64
 			func() {
 				data := make([]int16, len(sampleData))
 				copy(data, sampleData)
-				sort.Slice(data, func(i, j int) bool {
-					return data[i] < data[j]
-				})
+				sortOrdered(data)
 			},
 			func() {
 				data := make([]int16, len(sampleData))
@@ -144,9 +140,7 @@ func TuneSpecific(runs int, startSize int, increment int, deltaThreshold float64
 			func() {
 				data := make([]int32, len(sampleData))
 				copy(data, sampleData)
-				sort.Slice(data, func(i, j int) bool {
-					return data[i] < data[j]
-				})
+				sortOrdered(data)
 			},
 			func() {
 				data := make([]int32, len(sampleData))
@@ -180,9 +174,7 @@ func TuneSpecific(runs int, startSize int, increment int, deltaThreshold float64
 			func() {
 				data := make([]int64, len(sampleData))
 				copy(data, sampleData)
-				sort.Slice(data, func(i, j int) bool {
-					return data[i] < data[j]
-				})
+				sortOrdered(data)
 			},
 			func() {
 				data := make([]int64, len(sampleData))
@@ -216,9 +208,7 @@ func TuneSpecific(runs int, startSize int, increment int, deltaThreshold float64
 			func() {
 				data := make([]uint, len(sampleData))
 				copy(data, sampleData)
-				sort.Slice(data, func(i, j int) bool {
-					return data[i] < data[j]
-				})
+				sortOrdered(data)
 			},
 			func() {
 				data := make([]uint, len(sampleData))
@@ -252,9 +242,7 @@ func TuneSpecific(runs int, startSize int, increment int, deltaThreshold float64
 			func() {
 				data := make([]uint8, len(sampleData))
 				copy(data, sampleData)
-				sort.Slice(data, func(i, j int) bool {
-					return data[i] < data[j]
-				})
+				sortOrdered(data)
 			},
 			func() {
 				data := make([]uint8, len(sampleData))
@@ -288,9 +276,7 @@ func TuneSpecific(runs int, startSize int, increment int, deltaThreshold float64
 			func() {
 				data := make([]uint16, len(sampleData))
 				copy(data, sampleData)
-				sort.Slice(data, func(i, j int) bool {
-					return data[i] < data[j]
-				})
+				sortOrdered(data)
 			},
 			func() {
 				data := make([]uint16, len(sampleData))
@@ -324,9 +310,7 @@ func TuneSpecific(runs int, startSize int, increment int, deltaThreshold float64
 			func() {
 				data := make([]uint32, len(sampleData))
 				copy(data, sampleData)
-				sort.Slice(data, func(i, j int) bool {
-					return data[i] < data[j]
-				})
+				sortOrdered(data)
 			},
 			func() {
 				data := make([]uint32, len(sampleData))
@@ -360,9 +344,7 @@ func TuneSpecific(runs int, startSize int, increment int, deltaThreshold float64
 			func() {
 				data := make([]uint64, len(sampleData))
 				copy(data, sampleData)
-				sort.Slice(data, func(i, j int) bool {
-					return data[i] < data[j]
-				})
+				sortOrdered(data)
 			},
 			func() {
 				data := make([]uint64, len(sampleData))
@@ -396,9 +378,7 @@ func TuneSpecific(runs int, startSize int, increment int, deltaThreshold float64
 			func() {
 				data := make([]float32, len(sampleData))
 				copy(data, sampleData)
-				sort.Slice(data, func(i, j int) bool {
-					return data[i] < data[j]
-				})
+				sortOrdered(data)
 			},
 			func() {
 				data := make([]float32, len(sampleData))
@@ -500,9 +480,7 @@ func TuneSpecific(runs int, startSize int, increment int, deltaThreshold float64
 			func() {
 				data := make([]time.Time, len(sampleData))
 				copy(data, sampleData)
-				sort.Slice(data, func(i, j int) bool {
-					return data[i].Before(data[j])
-				})
+				sortTimes(data)
 			},
 			func() {
 				data := make([]time.Time, len(sampleData))
@@ -1,102 +1,102 @@
 package parsort
 
-import (
-	"sort"
-	"sync"
-)
-
 func UintAsc(data []uint) {
-	uintSort(data, false)
+	uintSort(data, false, defaultSorter())
 }
 
 func UintDesc(data []uint) {
-	uintSort(data, true)
-}
-
-func uintSort(data []uint, reverse bool) {
-	n := len(data)
-	if n < UintMinParallelSize {
-		sort.Slice(data, func(i, j int) bool {
-			return data[i] < data[j]
-		})
-		if reverse {
-			uintReverse(data)
-		}
-		return
-	}
+	uintSort(data, true, defaultSorter())
+}
 
-	chunkSize := (n + CoreCount - 1) / CoreCount
+// UintAsc sorts data in ascending order using the Sorter's configuration.
+// It returns an error when the Sorter's memory budget can't be met, leaving data untouched,
+// or a *PanicError when sorting panics.
+func (x *Sorter) UintAsc(data []uint) error {
+	return uintSort(data, false, x)
+}
 
-	chunks := make([][]uint, 0, CoreCount)
-	for i := 0; i < n; i += chunkSize {
-		end := i + chunkSize
-		if end > n {
-			end = n
-		}
-		chunks = append(chunks, data[i:end])
-	}
+// UintDesc sorts data in descending order using the Sorter's configuration.
+// It returns an error when the Sorter's memory budget can't be met, leaving data untouched,
+// or a *PanicError when sorting panics.
+func (x *Sorter) UintDesc(data []uint) error {
+	return uintSort(data, true, x)
+}
 
-	var wg sync.WaitGroup
-	for _, chunk := range chunks {
-		wg.Add(1)
-		go func(c []uint) {
-			defer wg.Done()
-			sort.Slice(c, func(i, j int) bool {
-				return c[i] < c[j]
-			})
-		}(chunk)
-	}
-	wg.Wait()
-
-	for len(chunks) > 1 {
-		mergedCount := (len(chunks) + 1) / 2
-		merged := make([][]uint, mergedCount)
-
-		var mWg sync.WaitGroup
-		for i := 0; i < len(chunks); i += 2 {
-			if i+1 == len(chunks) {
-				merged[i/2] = chunks[i]
-				continue
-			}
-			mWg.Add(1)
-			go func(i int) {
-				defer mWg.Done()
-				merged[i/2] = uintMergeSorted(chunks[i], chunks[i+1])
-			}(i)
-		}
-		mWg.Wait()
-		chunks = merged
-	}
+// UintAscAsync starts sorting data in ascending order in the background, see Handle for when data may be used.
+func UintAscAsync(data []uint) *Handle {
+	return async(func() error { return uintSort(data, false, returningSorter()) })
+}
+
+// UintDescAsync starts sorting data in descending order in the background, see Handle for when data may be used.
+func UintDescAsync(data []uint) *Handle {
+	return async(func() error { return uintSort(data, true, returningSorter()) })
+}
+
+// UintAscAsync is UintAscAsync using the Sorter's configuration, cancelled with the context of WithContext.
+func (x *Sorter) UintAscAsync(data []uint) *Handle {
+	return async(func() error { return uintSort(data, false, x) })
+}
+
+// UintDescAsync is UintDescAsync using the Sorter's configuration, cancelled with the context of WithContext.
+func (x *Sorter) UintDescAsync(data []uint) *Handle {
+	return async(func() error { return uintSort(data, true, x) })
+}
+
+// UintSorted returns a copy of src sorted in ascending order, leaving src untouched.
+func UintSorted(src []uint) []uint {
+	dst := make([]uint, len(src))
+	uintSortInto(dst, src, defaultSorter())
+	return dst
+}
+
+// UintSortedInto writes src sorted in ascending order to dst, leaving src untouched.
+// dst must have the length of src and must either be src or not overlap it.
+func UintSortedInto(dst, src []uint) {
+	uintSortInto(dst, src, defaultSorter())
+}
 
-	copy(data, chunks[0])
-	if reverse {
-		uintReverse(data)
+// UintSorted returns a copy of src sorted in ascending order using the Sorter's configuration.
+// It returns the errors of UintAsc, leaving src untouched.
+func (x *Sorter) UintSorted(src []uint) ([]uint, error) {
+	dst := make([]uint, len(src))
+	if err := uintSortInto(dst, src, x); err != nil {
+		return nil, err
 	}
+	return dst, nil
+}
+
+// UintSortedInto writes src sorted in ascending order to dst using the Sorter's configuration.
+// It returns the errors of UintAsc, leaving src untouched.
+func (x *Sorter) UintSortedInto(dst, src []uint) error {
+	return uintSortInto(dst, src, x)
+}
+
+// UintLazy sorts the chunks of data in parallel and returns an Iterator over its values in
+// ascending order, merging the chunks only as far as values are requested.
+func UintLazy(data []uint) *Iterator[uint] {
+	return lazySort(data, UintMinParallelSize, &uintOps)
+}
+
+var uintOps = typeOps[uint]{
+	less:     func(a, b uint) bool { return a < b },
+	sort:     sortOrdered[uint],
+	merge:    uintMergeSorted,
+	key:      func(v uint) uint64 { return uint64(v) },
+	keyBytes: 8,
+	reverse:  uintReverse,
+}
+
+func uintSort(data []uint, reverse bool, s *Sorter) error {
+	return sortSlice(data, reverse, s, UintMinParallelSize, &uintOps)
+}
+
+func uintSortInto(dst, src []uint, s *Sorter) error {
+	return sortSliceInto(dst, src, s, UintMinParallelSize, &uintOps)
 }
 
 func uintMergeSorted(a, b []uint) []uint {
 	res := make([]uint, len(a)+len(b))
-	i, j, k := 0, 0, 0
-	for i < len(a) && j < len(b) {
-		if a[i] <= b[j] {
-			res[k] = a[i]
-			i++
-		} else {
-			res[k] = b[j]
-			j++
-		}
-		k++
-	}
-	for i < len(a) {
-		res[k] = a[i]
-		i++
-		k++
-	}
-	for j < len(b) {
-		res[k] = b[j]
-		j++
-		k++
-	}
+	uintMergeKernel(res, a, b)
 	return res
 }
 
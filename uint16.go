@@ -1,77 +1,97 @@
 package parsort
 
-import (
-	"sort"
-	"sync"
-)
-
 func Uint16Asc(data []uint16) {
-	uint16Sort(data, false)
+	uint16Sort(data, false, defaultSorter())
 }
 
 func Uint16Desc(data []uint16) {
-	uint16Sort(data, true)
+	uint16Sort(data, true, defaultSorter())
 }
 
-func uint16Sort(data []uint16, reverse bool) {
-	n := len(data)
-	if n < Uint16MinParallelSize {
-		sort.Slice(data, func(i, j int) bool {
-			return data[i] < data[j]
-		})
-		if reverse {
-			uint16Reverse(data)
-		}
-		return
-	}
+// Uint16Asc sorts data in ascending order using the Sorter's configuration.
+// It returns an error when the Sorter's memory budget can't be met, leaving data untouched,
+// or a *PanicError when sorting panics.
+func (x *Sorter) Uint16Asc(data []uint16) error {
+	return uint16Sort(data, false, x)
+}
 
-	chunkSize := (n + CoreCount - 1) / CoreCount
+// Uint16Desc sorts data in descending order using the Sorter's configuration.
+// It returns an error when the Sorter's memory budget can't be met, leaving data untouched,
+// or a *PanicError when sorting panics.
+func (x *Sorter) Uint16Desc(data []uint16) error {
+	return uint16Sort(data, true, x)
+}
 
-	chunks := make([][]uint16, 0, CoreCount)
-	for i := 0; i < n; i += chunkSize {
-		end := i + chunkSize
-		if end > n {
-			end = n
-		}
-		chunks = append(chunks, data[i:end])
-	}
+// Uint16AscAsync starts sorting data in ascending order in the background, see Handle for when data may be used.
+func Uint16AscAsync(data []uint16) *Handle {
+	return async(func() error { return uint16Sort(data, false, returningSorter()) })
+}
 
-	var wg sync.WaitGroup
-	for _, chunk := range chunks {
-		wg.Add(1)
-		go func(c []uint16) {
-			defer wg.Done()
-			sort.Slice(c, func(i, j int) bool {
-				return c[i] < c[j]
-			})
-		}(chunk)
-	}
-	wg.Wait()
-
-	for len(chunks) > 1 {
-		mergedCount := (len(chunks) + 1) / 2
-		merged := make([][]uint16, mergedCount)
-
-		var mWg sync.WaitGroup
-		for i := 0; i < len(chunks); i += 2 {
-			if i+1 == len(chunks) {
-				merged[i/2] = chunks[i]
-				continue
-			}
-			mWg.Add(1)
-			go func(i int) {
-				defer mWg.Done()
-				merged[i/2] = uint16MergeSorted(chunks[i], chunks[i+1])
-			}(i)
-		}
-		mWg.Wait()
-		chunks = merged
-	}
+// Uint16DescAsync starts sorting data in descending order in the background, see Handle for when data may be used.
+func Uint16DescAsync(data []uint16) *Handle {
+	return async(func() error { return uint16Sort(data, true, returningSorter()) })
+}
+
+// Uint16AscAsync is Uint16AscAsync using the Sorter's configuration, cancelled with the context of WithContext.
+func (x *Sorter) Uint16AscAsync(data []uint16) *Handle {
+	return async(func() error { return uint16Sort(data, false, x) })
+}
+
+// Uint16DescAsync is Uint16DescAsync using the Sorter's configuration, cancelled with the context of WithContext.
+func (x *Sorter) Uint16DescAsync(data []uint16) *Handle {
+	return async(func() error { return uint16Sort(data, true, x) })
+}
+
+// Uint16Sorted returns a copy of src sorted in ascending order, leaving src untouched.
+func Uint16Sorted(src []uint16) []uint16 {
+	dst := make([]uint16, len(src))
+	uint16SortInto(dst, src, defaultSorter())
+	return dst
+}
+
+// Uint16SortedInto writes src sorted in ascending order to dst, leaving src untouched.
+// dst must have the length of src and must either be src or not overlap it.
+func Uint16SortedInto(dst, src []uint16) {
+	uint16SortInto(dst, src, defaultSorter())
+}
 
-	copy(data, chunks[0])
-	if reverse {
-		uint16Reverse(data)
+// Uint16Sorted returns a copy of src sorted in ascending order using the Sorter's configuration.
+// It returns the errors of Uint16Asc, leaving src untouched.
+func (x *Sorter) Uint16Sorted(src []uint16) ([]uint16, error) {
+	dst := make([]uint16, len(src))
+	if err := uint16SortInto(dst, src, x); err != nil {
+		return nil, err
 	}
+	return dst, nil
+}
+
+// Uint16SortedInto writes src sorted in ascending order to dst using the Sorter's configuration.
+// It returns the errors of Uint16Asc, leaving src untouched.
+func (x *Sorter) Uint16SortedInto(dst, src []uint16) error {
+	return uint16SortInto(dst, src, x)
+}
+
+// Uint16Lazy sorts the chunks of data in parallel and returns an Iterator over its values in
+// ascending order, merging the chunks only as far as values are requested.
+func Uint16Lazy(data []uint16) *Iterator[uint16] {
+	return lazySort(data, Uint16MinParallelSize, &uint16Ops)
+}
+
+var uint16Ops = typeOps[uint16]{
+	less:     func(a, b uint16) bool { return a < b },
+	sort:     sortOrdered[uint16],
+	merge:    uint16MergeSorted,
+	key:      func(v uint16) uint64 { return uint64(v) },
+	keyBytes: 2,
+	reverse:  uint16Reverse,
+}
+
+func uint16Sort(data []uint16, reverse bool, s *Sorter) error {
+	return sortSlice(data, reverse, s, Uint16MinParallelSize, &uint16Ops)
+}
+
+func uint16SortInto(dst, src []uint16, s *Sorter) error {
+	return sortSliceInto(dst, src, s, Uint16MinParallelSize, &uint16Ops)
 }
 
 func uint16MergeSorted(a, b []uint16) []uint16 {
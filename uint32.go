@@ -1,102 +1,102 @@
 package parsort
 
-import (
-	"sort"
-	"sync"
-)
-
 func Uint32Asc(data []uint32) {
-	uint32Sort(data, false)
+	uint32Sort(data, false, defaultSorter())
 }
 
 func Uint32Desc(data []uint32) {
-	uint32Sort(data, true)
-}
-
-func uint32Sort(data []uint32, reverse bool) {
-	n := len(data)
-	if n < Uint32MinParallelSize {
-		sort.Slice(data, func(i, j int) bool {
-			return data[i] < data[j]
-		})
-		if reverse {
-			uint32Reverse(data)
-		}
-		return
-	}
+	uint32Sort(data, true, defaultSorter())
+}
 
-	chunkSize := (n + CoreCount - 1) / CoreCount
+// Uint32Asc sorts data in ascending order using the Sorter's configuration.
+// It returns an error when the Sorter's memory budget can't be met, leaving data untouched,
+// or a *PanicError when sorting panics.
+func (x *Sorter) Uint32Asc(data []uint32) error {
+	return uint32Sort(data, false, x)
+}
 
-	chunks := make([][]uint32, 0, CoreCount)
-	for i := 0; i < n; i += chunkSize {
-		end := i + chunkSize
-		if end > n {
-			end = n
-		}
-		chunks = append(chunks, data[i:end])
-	}
+// Uint32Desc sorts data in descending order using the Sorter's configuration.
+// It returns an error when the Sorter's memory budget can't be met, leaving data untouched,
+// or a *PanicError when sorting panics.
+func (x *Sorter) Uint32Desc(data []uint32) error {
+	return uint32Sort(data, true, x)
+}
 
-	var wg sync.WaitGroup
-	for _, chunk := range chunks {
-		wg.Add(1)
-		go func(c []uint32) {
-			defer wg.Done()
-			sort.Slice(c, func(i, j int) bool {
-				return c[i] < c[j]
-			})
-		}(chunk)
-	}
-	wg.Wait()
-
-	for len(chunks) > 1 {
-		mergedCount := (len(chunks) + 1) / 2
-		merged := make([][]uint32, mergedCount)
-
-		var mWg sync.WaitGroup
-		for i := 0; i < len(chunks); i += 2 {
-			if i+1 == len(chunks) {
-				merged[i/2] = chunks[i]
-				continue
-			}
-			mWg.Add(1)
-			go func(i int) {
-				defer mWg.Done()
-				merged[i/2] = uint32MergeSorted(chunks[i], chunks[i+1])
-			}(i)
-		}
-		mWg.Wait()
-		chunks = merged
-	}
+// Uint32AscAsync starts sorting data in ascending order in the background, see Handle for when data may be used.
+func Uint32AscAsync(data []uint32) *Handle {
+	return async(func() error { return uint32Sort(data, false, returningSorter()) })
+}
+
+// Uint32DescAsync starts sorting data in descending order in the background, see Handle for when data may be used.
+func Uint32DescAsync(data []uint32) *Handle {
+	return async(func() error { return uint32Sort(data, true, returningSorter()) })
+}
+
+// Uint32AscAsync is Uint32AscAsync using the Sorter's configuration, cancelled with the context of WithContext.
+func (x *Sorter) Uint32AscAsync(data []uint32) *Handle {
+	return async(func() error { return uint32Sort(data, false, x) })
+}
+
+// Uint32DescAsync is Uint32DescAsync using the Sorter's configuration, cancelled with the context of WithContext.
+func (x *Sorter) Uint32DescAsync(data []uint32) *Handle {
+	return async(func() error { return uint32Sort(data, true, x) })
+}
+
+// Uint32Sorted returns a copy of src sorted in ascending order, leaving src untouched.
+func Uint32Sorted(src []uint32) []uint32 {
+	dst := make([]uint32, len(src))
+	uint32SortInto(dst, src, defaultSorter())
+	return dst
+}
+
+// Uint32SortedInto writes src sorted in ascending order to dst, leaving src untouched.
+// dst must have the length of src and must either be src or not overlap it.
+func Uint32SortedInto(dst, src []uint32) {
+	uint32SortInto(dst, src, defaultSorter())
+}
 
-	copy(data, chunks[0])
-	if reverse {
-		uint32Reverse(data)
+// Uint32Sorted returns a copy of src sorted in ascending order using the Sorter's configuration.
+// It returns the errors of Uint32Asc, leaving src untouched.
+func (x *Sorter) Uint32Sorted(src []uint32) ([]uint32, error) {
+	dst := make([]uint32, len(src))
+	if err := uint32SortInto(dst, src, x); err != nil {
+		return nil, err
 	}
+	return dst, nil
+}
+
+// Uint32SortedInto writes src sorted in ascending order to dst using the Sorter's configuration.
+// It returns the errors of Uint32Asc, leaving src untouched.
+func (x *Sorter) Uint32SortedInto(dst, src []uint32) error {
+	return uint32SortInto(dst, src, x)
+}
+
+// Uint32Lazy sorts the chunks of data in parallel and returns an Iterator over its values in
+// ascending order, merging the chunks only as far as values are requested.
+func Uint32Lazy(data []uint32) *Iterator[uint32] {
+	return lazySort(data, Uint32MinParallelSize, &uint32Ops)
+}
+
+var uint32Ops = typeOps[uint32]{
+	less:     func(a, b uint32) bool { return a < b },
+	sort:     sortOrdered[uint32],
+	merge:    uint32MergeSorted,
+	key:      func(v uint32) uint64 { return uint64(v) },
+	keyBytes: 4,
+	reverse:  uint32Reverse,
+}
+
+func uint32Sort(data []uint32, reverse bool, s *Sorter) error {
+	return sortSlice(data, reverse, s, Uint32MinParallelSize, &uint32Ops)
+}
+
+func uint32SortInto(dst, src []uint32, s *Sorter) error {
+	return sortSliceInto(dst, src, s, Uint32MinParallelSize, &uint32Ops)
 }
 
 func uint32MergeSorted(a, b []uint32) []uint32 {
 	res := make([]uint32, len(a)+len(b))
-	i, j, k := 0, 0, 0
-	for i < len(a) && j < len(b) {
-		if a[i] <= b[j] {
-			res[k] = a[i]
-			i++
-		} else {
-			res[k] = b[j]
-			j++
-		}
-		k++
-	}
-	for i < len(a) {
-		res[k] = a[i]
-		i++
-		k++
-	}
-	for j < len(b) {
-		res[k] = b[j]
-		j++
-		k++
-	}
+	uint32MergeKernel(res, a, b)
 	return res
 }
 
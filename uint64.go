@@ -1,102 +1,102 @@
 package parsort
 
-import (
-	"sort"
-	"sync"
-)
-
 func Uint64Asc(data []uint64) {
-	uint64Sort(data, false)
+	uint64Sort(data, false, defaultSorter())
 }
 
 func Uint64Desc(data []uint64) {
-	uint64Sort(data, true)
-}
-
-func uint64Sort(data []uint64, reverse bool) {
-	n := len(data)
-	if n < Uint64MinParallelSize {
-		sort.Slice(data, func(i, j int) bool {
-			return data[i] < data[j]
-		})
-		if reverse {
-			uint64Reverse(data)
-		}
-		return
-	}
+	uint64Sort(data, true, defaultSorter())
+}
 
-	chunkSize := (n + CoreCount - 1) / CoreCount
+// Uint64Asc sorts data in ascending order using the Sorter's configuration.
+// It returns an error when the Sorter's memory budget can't be met, leaving data untouched,
+// or a *PanicError when sorting panics.
+func (x *Sorter) Uint64Asc(data []uint64) error {
+	return uint64Sort(data, false, x)
+}
 
-	chunks := make([][]uint64, 0, CoreCount)
-	for i := 0; i < n; i += chunkSize {
-		end := i + chunkSize
-		if end > n {
-			end = n
-		}
-		chunks = append(chunks, data[i:end])
-	}
+// Uint64Desc sorts data in descending order using the Sorter's configuration.
+// It returns an error when the Sorter's memory budget can't be met, leaving data untouched,
+// or a *PanicError when sorting panics.
+func (x *Sorter) Uint64Desc(data []uint64) error {
+	return uint64Sort(data, true, x)
+}
 
-	var wg sync.WaitGroup
-	for _, chunk := range chunks {
-		wg.Add(1)
-		go func(c []uint64) {
-			defer wg.Done()
-			sort.Slice(c, func(i, j int) bool {
-				return c[i] < c[j]
-			})
-		}(chunk)
-	}
-	wg.Wait()
-
-	for len(chunks) > 1 {
-		mergedCount := (len(chunks) + 1) / 2
-		merged := make([][]uint64, mergedCount)
-
-		var mWg sync.WaitGroup
-		for i := 0; i < len(chunks); i += 2 {
-			if i+1 == len(chunks) {
-				merged[i/2] = chunks[i]
-				continue
-			}
-			mWg.Add(1)
-			go func(i int) {
-				defer mWg.Done()
-				merged[i/2] = uint64MergeSorted(chunks[i], chunks[i+1])
-			}(i)
-		}
-		mWg.Wait()
-		chunks = merged
-	}
+// Uint64AscAsync starts sorting data in ascending order in the background, see Handle for when data may be used.
+func Uint64AscAsync(data []uint64) *Handle {
+	return async(func() error { return uint64Sort(data, false, returningSorter()) })
+}
+
+// Uint64DescAsync starts sorting data in descending order in the background, see Handle for when data may be used.
+func Uint64DescAsync(data []uint64) *Handle {
+	return async(func() error { return uint64Sort(data, true, returningSorter()) })
+}
+
+// Uint64AscAsync is Uint64AscAsync using the Sorter's configuration, cancelled with the context of WithContext.
+func (x *Sorter) Uint64AscAsync(data []uint64) *Handle {
+	return async(func() error { return uint64Sort(data, false, x) })
+}
+
+// Uint64DescAsync is Uint64DescAsync using the Sorter's configuration, cancelled with the context of WithContext.
+func (x *Sorter) Uint64DescAsync(data []uint64) *Handle {
+	return async(func() error { return uint64Sort(data, true, x) })
+}
+
+// Uint64Sorted returns a copy of src sorted in ascending order, leaving src untouched.
+func Uint64Sorted(src []uint64) []uint64 {
+	dst := make([]uint64, len(src))
+	uint64SortInto(dst, src, defaultSorter())
+	return dst
+}
+
+// Uint64SortedInto writes src sorted in ascending order to dst, leaving src untouched.
+// dst must have the length of src and must either be src or not overlap it.
+func Uint64SortedInto(dst, src []uint64) {
+	uint64SortInto(dst, src, defaultSorter())
+}
 
-	copy(data, chunks[0])
-	if reverse {
-		uint64Reverse(data)
+// Uint64Sorted returns a copy of src sorted in ascending order using the Sorter's configuration.
+// It returns the errors of Uint64Asc, leaving src untouched.
+func (x *Sorter) Uint64Sorted(src []uint64) ([]uint64, error) {
+	dst := make([]uint64, len(src))
+	if err := uint64SortInto(dst, src, x); err != nil {
+		return nil, err
 	}
+	return dst, nil
+}
+
+// Uint64SortedInto writes src sorted in ascending order to dst using the Sorter's configuration.
+// It returns the errors of Uint64Asc, leaving src untouched.
+func (x *Sorter) Uint64SortedInto(dst, src []uint64) error {
+	return uint64SortInto(dst, src, x)
+}
+
+// Uint64Lazy sorts the chunks of data in parallel and returns an Iterator over its values in
+// ascending order, merging the chunks only as far as values are requested.
+func Uint64Lazy(data []uint64) *Iterator[uint64] {
+	return lazySort(data, Uint64MinParallelSize, &uint64Ops)
+}
+
+var uint64Ops = typeOps[uint64]{
+	less:     func(a, b uint64) bool { return a < b },
+	sort:     sortOrdered[uint64],
+	merge:    uint64MergeSorted,
+	key:      func(v uint64) uint64 { return uint64(v) },
+	keyBytes: 8,
+	reverse:  uint64Reverse,
+}
+
+func uint64Sort(data []uint64, reverse bool, s *Sorter) error {
+	return sortSlice(data, reverse, s, Uint64MinParallelSize, &uint64Ops)
+}
+
+func uint64SortInto(dst, src []uint64, s *Sorter) error {
+	return sortSliceInto(dst, src, s, Uint64MinParallelSize, &uint64Ops)
 }
 
 func uint64MergeSorted(a, b []uint64) []uint64 {
 	res := make([]uint64, len(a)+len(b))
-	i, j, k := 0, 0, 0
-	for i < len(a) && j < len(b) {
-		if a[i] <= b[j] {
-			res[k] = a[i]
-			i++
-		} else {
-			res[k] = b[j]
-			j++
-		}
-		k++
-	}
-	for i < len(a) {
-		res[k] = a[i]
-		i++
-		k++
-	}
-	for j < len(b) {
-		res[k] = b[j]
-		j++
-		k++
-	}
+	uint64MergeKernel(res, a, b)
 	return res
 }
 
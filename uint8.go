@@ -1,77 +1,97 @@
 package parsort
 
-import (
-	"sort"
-	"sync"
-)
-
 func Uint8Asc(data []uint8) {
-	uint8Sort(data, false)
+	uint8Sort(data, false, defaultSorter())
 }
 
 func Uint8Desc(data []uint8) {
-	uint8Sort(data, true)
+	uint8Sort(data, true, defaultSorter())
 }
 
-func uint8Sort(data []uint8, reverse bool) {
-	n := len(data)
-	if n < Uint8MinParallelSize {
-		sort.Slice(data, func(i, j int) bool {
-			return data[i] < data[j]
-		})
-		if reverse {
-			uint8Reverse(data)
-		}
-		return
-	}
+// Uint8Asc sorts data in ascending order using the Sorter's configuration.
+// It returns an error when the Sorter's memory budget can't be met, leaving data untouched,
+// or a *PanicError when sorting panics.
+func (x *Sorter) Uint8Asc(data []uint8) error {
+	return uint8Sort(data, false, x)
+}
 
-	chunkSize := (n + CoreCount - 1) / CoreCount
+// Uint8Desc sorts data in descending order using the Sorter's configuration.
+// It returns an error when the Sorter's memory budget can't be met, leaving data untouched,
+// or a *PanicError when sorting panics.
+func (x *Sorter) Uint8Desc(data []uint8) error {
+	return uint8Sort(data, true, x)
+}
 
-	chunks := make([][]uint8, 0, CoreCount)
-	for i := 0; i < n; i += chunkSize {
-		end := i + chunkSize
-		if end > n {
-			end = n
-		}
-		chunks = append(chunks, data[i:end])
-	}
+// Uint8AscAsync starts sorting data in ascending order in the background, see Handle for when data may be used.
+func Uint8AscAsync(data []uint8) *Handle {
+	return async(func() error { return uint8Sort(data, false, returningSorter()) })
+}
 
-	var wg sync.WaitGroup
-	for _, chunk := range chunks {
-		wg.Add(1)
-		go func(c []uint8) {
-			defer wg.Done()
-			sort.Slice(c, func(i, j int) bool {
-				return c[i] < c[j]
-			})
-		}(chunk)
-	}
-	wg.Wait()
-
-	for len(chunks) > 1 {
-		mergedCount := (len(chunks) + 1) / 2
-		merged := make([][]uint8, mergedCount)
-
-		var mWg sync.WaitGroup
-		for i := 0; i < len(chunks); i += 2 {
-			if i+1 == len(chunks) {
-				merged[i/2] = chunks[i]
-				continue
-			}
-			mWg.Add(1)
-			go func(i int) {
-				defer mWg.Done()
-				merged[i/2] = uint8MergeSorted(chunks[i], chunks[i+1])
-			}(i)
-		}
-		mWg.Wait()
-		chunks = merged
-	}
+// Uint8DescAsync starts sorting data in descending order in the background, see Handle for when data may be used.
+func Uint8DescAsync(data []uint8) *Handle {
+	return async(func() error { return uint8Sort(data, true, returningSorter()) })
+}
+
+// Uint8AscAsync is Uint8AscAsync using the Sorter's configuration, cancelled with the context of WithContext.
+func (x *Sorter) Uint8AscAsync(data []uint8) *Handle {
+	return async(func() error { return uint8Sort(data, false, x) })
+}
+
+// Uint8DescAsync is Uint8DescAsync using the Sorter's configuration, cancelled with the context of WithContext.
+func (x *Sorter) Uint8DescAsync(data []uint8) *Handle {
+	return async(func() error { return uint8Sort(data, true, x) })
+}
+
+// Uint8Sorted returns a copy of src sorted in ascending order, leaving src untouched.
+func Uint8Sorted(src []uint8) []uint8 {
+	dst := make([]uint8, len(src))
+	uint8SortInto(dst, src, defaultSorter())
+	return dst
+}
+
+// Uint8SortedInto writes src sorted in ascending order to dst, leaving src untouched.
+// dst must have the length of src and must either be src or not overlap it.
+func Uint8SortedInto(dst, src []uint8) {
+	uint8SortInto(dst, src, defaultSorter())
+}
 
-	copy(data, chunks[0])
-	if reverse {
-		uint8Reverse(data)
+// Uint8Sorted returns a copy of src sorted in ascending order using the Sorter's configuration.
+// It returns the errors of Uint8Asc, leaving src untouched.
+func (x *Sorter) Uint8Sorted(src []uint8) ([]uint8, error) {
+	dst := make([]uint8, len(src))
+	if err := uint8SortInto(dst, src, x); err != nil {
+		return nil, err
 	}
+	return dst, nil
+}
+
+// Uint8SortedInto writes src sorted in ascending order to dst using the Sorter's configuration.
+// It returns the errors of Uint8Asc, leaving src untouched.
+func (x *Sorter) Uint8SortedInto(dst, src []uint8) error {
+	return uint8SortInto(dst, src, x)
+}
+
+// Uint8Lazy sorts the chunks of data in parallel and returns an Iterator over its values in
+// ascending order, merging the chunks only as far as values are requested.
+func Uint8Lazy(data []uint8) *Iterator[uint8] {
+	return lazySort(data, Uint8MinParallelSize, &uint8Ops)
+}
+
+var uint8Ops = typeOps[uint8]{
+	less:     func(a, b uint8) bool { return a < b },
+	sort:     sortOrdered[uint8],
+	merge:    uint8MergeSorted,
+	key:      func(v uint8) uint64 { return uint64(v) },
+	keyBytes: 1,
+	reverse:  uint8Reverse,
+}
+
+func uint8Sort(data []uint8, reverse bool, s *Sorter) error {
+	return sortSlice(data, reverse, s, Uint8MinParallelSize, &uint8Ops)
+}
+
+func uint8SortInto(dst, src []uint8, s *Sorter) error {
+	return sortSliceInto(dst, src, s, Uint8MinParallelSize, &uint8Ops)
 }
 
 func uint8MergeSorted(a, b []uint8) []uint8 {
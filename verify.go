@@ -0,0 +1,173 @@
+package parsort
+
+import (
+	"encoding/binary"
+	"fmt"
+	"log"
+	"os"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unsafe"
+)
+
+// VerifyError describes a sort whose output failed the checks enabled by Verify.
+type VerifyError struct {
+	// Type is the element type of the slice.
+	Type string
+	// N is the length of the slice.
+	N int
+	// Decision is how the failed sort was executed.
+	Decision Decision
+	// Unsorted is the first position i where data[i+1] sorts before data[i], -1 when the output is in order.
+	Unsorted int
+	// NotPermutation is set when the output isn't a permutation of the input.
+	NotPermutation bool
+}
+
+func (x *VerifyError) Error() string {
+	msg := fmt.Sprintf("parsort: verification failed sorting %d %s with %v:", x.N, x.Type, x.Decision.Strategy)
+	if x.Unsorted >= 0 {
+		msg += fmt.Sprintf(" data[%d] and data[%d] are out of order", x.Unsorted, x.Unsorted+1)
+		if x.NotPermutation {
+			msg += " and"
+		}
+	}
+	if x.NotPermutation {
+		msg += " the output isn't a permutation of the input"
+	}
+	return msg
+}
+
+// verifyFromEnv reports whether the PARSORT_VERIFY environment variable enables Verify.
+func verifyFromEnv() bool {
+	switch os.Getenv("PARSORT_VERIFY") {
+	case "", "0", "false":
+		return false
+	}
+	return true
+}
+
+// reportVerifyError passes err to the hook of s, or logs it when there is none.
+func reportVerifyError(s *Sorter, err *VerifyError) {
+	if s.verifyHook != nil {
+		s.verifyHook(err)
+		return
+	}
+	log.Print(err)
+}
+
+// multisetSeed randomises the element hashes of the process, so inputs can't be crafted to collide.
+var multisetSeed = uint64(time.Now().UnixNano())
+
+// byteRanges caches, per element type, the ranges of bytes holding values, that is everything but padding.
+var byteRanges sync.Map
+
+type byteRange struct{ off, size uintptr }
+
+// valueRanges returns the non padding byte ranges of t, merging adjacent ones.
+func valueRanges(t reflect.Type) []byteRange {
+	if r, ok := byteRanges.Load(t); ok {
+		return r.([]byteRange)
+	}
+	var ranges []byteRange
+	var walk func(t reflect.Type, off uintptr)
+	walk = func(t reflect.Type, off uintptr) {
+		switch t.Kind() {
+		case reflect.Struct:
+			for i := 0; i < t.NumField(); i++ {
+				f := t.Field(i)
+				walk(f.Type, off+f.Offset)
+			}
+		case reflect.Array:
+			for i := 0; i < t.Len(); i++ {
+				walk(t.Elem(), off+uintptr(i)*t.Elem().Size())
+			}
+		default:
+			if t.Size() == 0 {
+				return
+			}
+			if k := len(ranges) - 1; k >= 0 && ranges[k].off+ranges[k].size == off {
+				ranges[k].size += t.Size()
+				return
+			}
+			ranges = append(ranges, byteRange{off, t.Size()})
+		}
+	}
+	walk(t, 0)
+	byteRanges.Store(t, ranges)
+	return ranges
+}
+
+func mix64(h uint64) uint64 {
+	h ^= h >> 33
+	h *= 0xff51afd7ed558ccd
+	h ^= h >> 33
+	h *= 0xc4ceb9fe1a85ec53
+	h ^= h >> 33
+	return h
+}
+
+// multisetHash returns a hash of the elements of data that doesn't depend on their order.
+// Elements are hashed by their memory contents, padding excluded, so moving elements around
+// keeps the hash while losing or duplicating one changes it.
+func multisetHash[T any](data []T) uint64 {
+	ranges := valueRanges(reflect.TypeOf((*T)(nil)).Elem())
+	chunks := splitChunks(len(data))
+	sums := make([]uint64, len(chunks))
+	runTasks(len(chunks), func(c int) {
+		var sum uint64
+		for i := chunks[c].start; i < chunks[c].end; i++ {
+			p := unsafe.Pointer(&data[i])
+			h := multisetSeed
+			for _, r := range ranges {
+				b := unsafe.Slice((*byte)(unsafe.Add(p, r.off)), r.size)
+				for len(b) >= 8 {
+					h = mix64(h ^ binary.LittleEndian.Uint64(b))
+					b = b[8:]
+				}
+				if len(b) > 0 {
+					var tail [8]byte
+					copy(tail[:], b)
+					h = mix64(h ^ binary.LittleEndian.Uint64(tail[:]) ^ uint64(len(b))<<56)
+				}
+			}
+			sum += mix64(h)
+		}
+		sums[c] = sum
+	})
+
+	total := uint64(len(data))
+	for _, s := range sums {
+		total += s
+	}
+	return total
+}
+
+// firstUnsorted returns the first i where less(data[i+1], data[i]), or -1 when data is sorted.
+// The chunks are checked in parallel, each checking the pair it shares with the next one.
+func firstUnsorted[T any](data []T, less func(a, b T) bool) int {
+	n := len(data)
+	if n < 2 {
+		return -1
+	}
+	chunks := splitChunks(n - 1)
+	first := int64(-1)
+	runTasks(len(chunks), func(c int) {
+		for i := chunks[c].start; i < chunks[c].end; i++ {
+			if less(data[i+1], data[i]) {
+				for {
+					cur := atomic.LoadInt64(&first)
+					if cur >= 0 && cur <= int64(i) {
+						return
+					}
+					if atomic.CompareAndSwapInt64(&first, cur, int64(i)) {
+						return
+					}
+				}
+			}
+		}
+	})
+	return int(first)
+}
@@ -0,0 +1,98 @@
+package parsort
+
+import (
+	"strings"
+	"testing"
+)
+
+type paddedRecord struct {
+	A int8
+	B int64
+	C int16
+	D string
+}
+
+func TestVerify_NoFalsePositives(t *testing.T) {
+	withCoreCount(t, 4)
+	var failures []*VerifyError
+	hook := func(err *VerifyError) {
+		failures = append(failures, err)
+	}
+
+	for _, st := range strategies {
+		s := NewSorter().SetStrategy(st).SetVerify(true, hook)
+		s.IntDesc(genInts(50000))
+		s.Float64Asc(genFloats(50000))
+		s.StringDesc(genStrings(50000))
+		s.TimeAsc(genTimes(50000))
+		StructAscStableWith(s, genPeople(50000), func(a, b person) bool { return a.Age < b.Age })
+
+		padded := make([]paddedRecord, 50000)
+		for i := range padded {
+			padded[i] = paddedRecord{A: int8(i), B: int64(i * 7919 % 1000), C: int16(i), D: "x"}
+		}
+		StructDescWith(s, padded, func(a, b paddedRecord) bool { return a.B < b.B })
+	}
+	for _, f := range failures {
+		t.Errorf("unexpected verification failure: %v", f)
+	}
+}
+
+func TestVerify_DetectsUnsorted(t *testing.T) {
+	withCoreCount(t, 4)
+	data := genInts(100000)
+	intOps.sort(data)
+	data[70000] = data[70001] + 1
+	if got := firstUnsorted(data, intOps.less); got != 70000 {
+		t.Errorf("expected data[70000] to be out of order, got %d", got)
+	}
+	if got := firstUnsorted(data[:60000], intOps.less); got != -1 {
+		t.Errorf("expected a sorted prefix, got %d", got)
+	}
+}
+
+func TestVerify_DetectsLostElements(t *testing.T) {
+	withCoreCount(t, 4)
+	people := genPeople(100000)
+	h := multisetHash(people)
+
+	moved := append([]person(nil), people...)
+	StructAscStable(moved, func(a, b person) bool { return a.Name < b.Name })
+	if multisetHash(moved) != h {
+		t.Errorf("expected a permutation to keep the hash")
+	}
+
+	moved[10] = moved[11]
+	if multisetHash(moved) == h {
+		t.Errorf("expected a duplicated element to change the hash")
+	}
+}
+
+func TestVerify_ReportsThroughHook(t *testing.T) {
+	withCoreCount(t, 4)
+	var got *VerifyError
+	s := NewSorter().SetStrategy(PingPongMerge).SetVerify(true, func(err *VerifyError) {
+		got = err
+	})
+
+	// less changes its mind halfway through the sort.
+	calls := 0
+	data := genTimes(50000)
+	s.TimeAsc(data)
+	if got != nil {
+		t.Fatalf("unexpected failure: %v", got)
+	}
+	StructAscWith(s, genPeople(1000), func(a, b person) bool {
+		calls++
+		if calls > 5000 {
+			return a.Age > b.Age
+		}
+		return a.Age < b.Age
+	})
+	if got == nil || got.Unsorted < 0 || got.NotPermutation {
+		t.Fatalf("expected an out of order failure, got %v", got)
+	}
+	if !strings.Contains(got.Error(), "out of order") || got.Type != "parsort.person" {
+		t.Errorf("unexpected report %q for %s", got.Error(), got.Type)
+	}
+}